@@ -1,7 +1,140 @@
 package godradis
 
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
 type Attachment struct {
 	Filename string `json:"filename"`
 	Link string `json:"link"`
+	Size int64 `json:"size"`
+	ContentType string `json:"content_type"`
 	Node *Node
-}
\ No newline at end of file
+}
+
+// projectId returns the ID of the attachment's project, or an error if the attachment has no Node reference or its
+// Node has no Project reference
+func (a *Attachment) projectId() (int, error) {
+	if a.Node == nil {
+		return 0, errors.New(fmt.Sprintf("attachment %s has no Node reference", a.Filename))
+	}
+	return a.Node.projectId()
+}
+
+// markup returns the Dradis image markup that embeds the attachment in a field, e.g.
+// "!/pro/projects/1/nodes/5/attachments/screenshot.png!"
+func (a *Attachment) markup() (string, error) {
+	path, err := a.path()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("!%s!", path), nil
+}
+
+// path returns the server path of the attachment that Dradis markup refers to
+func (a *Attachment) path() (string, error) {
+	projectId, err := a.projectId()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/pro/projects/%v/nodes/%v/attachments/%s", projectId, a.Node.Id, url.PathEscape(a.Filename)), nil
+}
+
+// isImage reports whether the attachment is an image, going by its content type if known and its file extension if not
+func (a *Attachment) isImage() bool {
+	if a.ContentType != "" {
+		return strings.HasPrefix(strings.ToLower(a.ContentType), "image/")
+	}
+	switch strings.ToLower(filepath.Ext(a.Filename)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".bmp", ".svg", ".webp":
+		return true
+	}
+	return false
+}
+
+/*
+AttachmentMarkup returns the Textile markup that refers to the attachment from issue, evidence or note content. Images are
+embedded inline as !path! and other files are linked by name as "name":path. The path is built from the attachment's
+node and project, falling back to its Link if it has no Node or Project reference.
+
+    attachments, _ := gd.UploadAttachments(&node, []string{"/tmp/xss.png", "/tmp/request.txt"})
+    fields.Set("Screenshot", godradis.AttachmentMarkup(&attachments[0]))
+    fields.Set("Request", godradis.AttachmentMarkup(&attachments[1]))
+ */
+func AttachmentMarkup(attachment *Attachment) string {
+	path, err := attachment.path()
+	if err != nil {
+		path = attachment.Link
+	}
+	if attachment.isImage() {
+		return fmt.Sprintf("!%s!", path)
+	}
+	return fmt.Sprintf("\"%s\":%s", attachment.Filename, path)
+}
+
+/*
+DownloadURL returns the absolute URL of the attachment's file. Dradis usually returns Link relative to the server, so it
+is resolved against gd.Config.BaseUrl; an absolute Link is returned unchanged. A Link starting with "/" is taken to be
+relative to the Dradis root, so if BaseUrl has a path (e.g. https://example.com/dradis) the path is kept in front of it.
+The URL needs the same Authorization header as any other API request.
+
+    attachment, _ := gd.GetAttachmentByName(&node, "screenshot.png")
+    req, _ := http.NewRequest("GET", attachment.DownloadURL(&gd), nil)
+ */
+func (a *Attachment) DownloadURL(gd *Godradis) string {
+	base, err := url.Parse(strings.TrimSuffix(gd.Config.BaseUrl, "/") + "/")
+	if err != nil {
+		return a.Link
+	}
+	ref := a.Link
+	prefix := strings.TrimSuffix(base.EscapedPath(), "/")
+	if strings.HasPrefix(ref, "/") && !strings.HasPrefix(ref, "//") && prefix != "" && !strings.HasPrefix(ref, prefix+"/") {
+		ref = prefix + ref
+	}
+	link, err := url.Parse(ref)
+	if err != nil {
+		return a.Link
+	}
+	return base.ResolveReference(link).String()
+}
+
+// UploadProgressFunc is called by UploadAttachments as the upload is sent, with the number of bytes of the request body
+// sent so far and the total size of the body. If the upload is retried the count starts again from 0.
+type UploadProgressFunc func(bytesSent, total int64)
+
+// progressReader reports the bytes read through it to an UploadProgressFunc
+type progressReader struct {
+	io.ReadCloser
+	sent int64
+	total int64
+	progress UploadProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.progress(r.sent, r.total)
+	}
+	return n, err
+}
+
+// trackProgress wraps req's body, and the copies made for retries, so that progress is called as the body is sent
+func trackProgress(req *http.Request, progress UploadProgressFunc) {
+	total := req.ContentLength
+	req.Body = &progressReader{ReadCloser: req.Body, total: total, progress: progress}
+	getBody := req.GetBody
+	req.GetBody = func() (io.ReadCloser, error) {
+		body, err := getBody()
+		if err != nil {
+			return nil, err
+		}
+		return &progressReader{ReadCloser: body, total: total, progress: progress}, nil
+	}
+}
@@ -0,0 +1,356 @@
+package godradis
+
+import (
+	"context"
+	"fmt"
+	"github.com/iancoleman/orderedmap"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetAllAttachmentsMetadataFromListing(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pro/api/nodes/5/attachments" || r.Header.Get("Dradis-Project-Id") != "1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"filename": "shot.png", "link": "/pro/projects/1/nodes/5/attachments/shot.png",
+			"size": 1234, "content_type": "image/png"}]`))
+	}))
+	node := Node{Id: 5, Project: &Project{Id: 1}}
+	attachments, err := gd.GetAllAttachments(&node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) != 1 {
+		t.Fatalf("got %v attachments, want 1", len(attachments))
+	}
+	a := attachments[0]
+	if a.Size != 1234 || a.ContentType != "image/png" || a.Node != &node {
+		t.Errorf("got %+v", a)
+	}
+}
+
+func TestGetAttachmentMetadata(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "HEAD" || r.URL.Path != "/pro/projects/1/nodes/5/attachments/shot.png" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if r.Header.Get("Authorization") != `Token token="test-key"` {
+			t.Errorf("missing API key on same-host request")
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Length", "2048")
+	}))
+	a := Attachment{Filename: "shot.png", Link: "/pro/projects/1/nodes/5/attachments/shot.png"}
+	if err := gd.GetAttachmentMetadata(&a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Size != 2048 || a.ContentType != "image/png" {
+		t.Errorf("got size %v and content type %q", a.Size, a.ContentType)
+	}
+}
+
+func TestGetAttachmentMetadataOtherHost(t *testing.T) {
+	_, other := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("API key sent to another host")
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+	}))
+	gd, _ := newTestClient(t, http.NotFoundHandler())
+	a := Attachment{Filename: "report.pdf", Link: other.URL + "/report.pdf"}
+	if err := gd.GetAttachmentMetadata(&a); err != nil {
+		t.Fatal(err)
+	}
+	if a.ContentType != "application/pdf" {
+		t.Errorf("got content type %q", a.ContentType)
+	}
+}
+
+func TestGetAttachmentMetadataLoginPage(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}))
+	a := Attachment{Filename: "shot.png", Link: "/pro/projects/1/nodes/5/attachments/shot.png"}
+	if err := gd.GetAttachmentMetadata(&a); err == nil {
+		t.Fatal("expected an error for an HTML response")
+	}
+	if a.ContentType != "" || !a.isImage() {
+		t.Errorf("attachment changed by a failed lookup: %+v", a)
+	}
+}
+
+func TestDownloadURL(t *testing.T) {
+	tests := []struct {
+		baseUrl string
+		link string
+		want string
+	}{
+		{"https://example.com", "/pro/projects/1/nodes/5/attachments/shot.png",
+			"https://example.com/pro/projects/1/nodes/5/attachments/shot.png"},
+		{"https://example.com/", "/pro/a%20b.png", "https://example.com/pro/a%20b.png"},
+		{"https://example.com/dradis", "/pro/projects/1/nodes/5/attachments/shot.png",
+			"https://example.com/dradis/pro/projects/1/nodes/5/attachments/shot.png"},
+		{"https://example.com/dradis/", "/dradis/pro/shot.png", "https://example.com/dradis/pro/shot.png"},
+		{"https://example.com/dradis", "pro/shot.png", "https://example.com/dradis/pro/shot.png"},
+		{"https://example.com/dradis", "https://files.example.com/shot.png", "https://files.example.com/shot.png"},
+		{"https://example.com/dradis", "//files.example.com/shot.png", "https://files.example.com/shot.png"},
+	}
+	for _, tt := range tests {
+		gd := Godradis{Config: Config{BaseUrl: tt.baseUrl}}
+		a := Attachment{Link: tt.link}
+		if got := a.DownloadURL(&gd); got != tt.want {
+			t.Errorf("DownloadURL(%q, %q) = %q, want %q", tt.baseUrl, tt.link, got, tt.want)
+		}
+	}
+}
+
+func writeTempFile(t *testing.T, name string, content []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestUploadAttachmentsContextCancel(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server only notices the client going away once the body has been read
+		ioutil.ReadAll(r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+			t.Errorf("upload was not abandoned")
+		}
+	}))
+	path := writeTempFile(t, "shot.png", make([]byte, 1<<16))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := gd.UploadAttachmentsContext(ctx, &Node{Id: 5, Project: &Project{Id: 1}}, []string{path})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("upload took %v to give up", elapsed)
+	}
+}
+
+func TestUploadAttachmentsContextCancelDuringBackoff(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	gd.Config.MaxRetries = 5
+	path := writeTempFile(t, "shot.png", []byte("png"))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := gd.UploadAttachmentsContext(ctx, &Node{Id: 5, Project: &Project{Id: 1}}, []string{path})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("retries took %v to give up", elapsed)
+	}
+}
+
+func TestUploadAttachments(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("files[]")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		content, _ := ioutil.ReadAll(file)
+		if header.Filename != "shot.png" || string(content) != "png" {
+			t.Errorf("got %s %q", header.Filename, content)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`[{"filename": "shot.png", "link": "/pro/projects/1/nodes/5/attachments/shot.png"}]`))
+	}))
+	path := writeTempFile(t, "shot.png", []byte("png"))
+	var sent, total int64
+	node := Node{Id: 5, Project: &Project{Id: 1}}
+	attachments, err := gd.UploadAttachments(&node, []string{path}, func(bytesSent, bodySize int64) {
+		sent, total = bytesSent, bodySize
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) != 1 || attachments[0].Filename != "shot.png" || attachments[0].Node != &node {
+		t.Errorf("got %+v", attachments)
+	}
+	if sent == 0 || sent != total {
+		t.Errorf("progress reported %v of %v bytes", sent, total)
+	}
+}
+
+func TestUploadAttachmentsIfMissing(t *testing.T) {
+	var uploaded []string
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" {
+			w.Write([]byte(`[{"filename": "shot.png", "link": "/pro/projects/1/nodes/5/attachments/shot.png"}]`))
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error(err)
+			return
+		}
+		for _, header := range r.MultipartForm.File["files[]"] {
+			uploaded = append(uploaded, header.Filename)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`[{"filename": "scan.txt", "link": "/pro/projects/1/nodes/5/attachments/scan.txt"}]`))
+	}))
+	shot := writeTempFile(t, "shot.png", []byte("png"))
+	scan := writeTempFile(t, "scan.txt", []byte("nmap"))
+	node := Node{Id: 5, Project: &Project{Id: 1}}
+	attachments, skipped, err := gd.UploadAttachmentsIfMissing(&node, []string{shot, scan})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) != 1 || attachments[0].Filename != "scan.txt" {
+		t.Errorf("got attachments %+v", attachments)
+	}
+	if !reflect.DeepEqual(skipped, []string{shot}) {
+		t.Errorf("got skipped %v, want %v", skipped, []string{shot})
+	}
+	if !reflect.DeepEqual(uploaded, []string{"scan.txt"}) {
+		t.Errorf("got uploads %v, want only scan.txt", uploaded)
+	}
+}
+
+func TestAttachScreenshotToEvidence(t *testing.T) {
+	var evidenceBody string
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		switch r.URL.Path {
+		case "/pro/api/nodes/5/attachments":
+			// The server renames uploads that clash with an existing attachment
+			w.Write([]byte(`[{"filename": "xss-1.png", "link": "/pro/projects/1/nodes/5/attachments/xss-1.png"}]`))
+		case "/pro/api/nodes/5/evidence":
+			body, _ := ioutil.ReadAll(r.Body)
+			evidenceBody = string(body)
+			w.Write([]byte(`{"id": 8, "content": ""}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	path := writeTempFile(t, "xss.png", []byte("png"))
+	node := Node{Id: 5, Project: &Project{Id: 1}}
+	issue := Issue{Id: 3, Project: node.Project}
+	fields := orderedmap.New()
+	fields.Set("Port", "443/tcp")
+	fields.Set("Screenshot", "Login page:")
+	attachment, evidence, err := gd.AttachScreenshotToEvidence(&node, &issue, path, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attachment.Filename != "xss-1.png" || evidence.Id != 8 {
+		t.Errorf("got attachment %s and evidence %v", attachment.Filename, evidence.Id)
+	}
+	want := `#[Port]#\r\n443/tcp\r\n\r\n#[Screenshot]#\r\nLogin page:\r\n\r\n!/pro/projects/1/nodes/5/attachments/xss-1.png!`
+	if !strings.Contains(evidenceBody, want) {
+		t.Errorf("got evidence body %s, want it to contain %s", evidenceBody, want)
+	}
+	if screenshot, _ := fields.Get("Screenshot"); screenshot != "Login page:" {
+		t.Errorf("the caller's fields were changed to %v", screenshot)
+	}
+}
+
+func TestAttachmentStats(t *testing.T) {
+	const nodeCount = 10
+	var inFlight, maxInFlight int32
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/pro/api/nodes" {
+			var nodes []string
+			for id := 1; id <= nodeCount; id++ {
+				nodes = append(nodes, fmt.Sprintf(`{"id": %v, "label": "10.0.0.%v"}`, id, id))
+			}
+			w.Write([]byte("[" + strings.Join(nodes, ",") + "]"))
+			return
+		}
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		var id int
+		fmt.Sscanf(r.URL.Path, "/pro/api/nodes/%d/attachments", &id)
+		switch {
+		case id == 7:
+			w.WriteHeader(http.StatusInternalServerError)
+		case id%2 == 0:
+			// Even nodes have two attachments, one of them without a size
+			fmt.Fprintf(w, `[{"filename": "a.png", "size": %v}, {"filename": "b.png"}]`, id*100)
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	count, size, err := gd.AttachmentStats(&Project{Id: 1})
+	if err == nil || !strings.Contains(err.Error(), "could not list attachments on 1 of 10 nodes: node 7") {
+		t.Errorf("got error %v", err)
+	}
+	// Nodes 2, 4, 6, 8 and 10
+	if count != 10 || size != 3000 {
+		t.Errorf("got %v attachments and %v bytes, want 10 and 3000", count, size)
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > maxConcurrentRequests {
+		t.Errorf("got %v concurrent requests, want at most %v", max, maxConcurrentRequests)
+	}
+}
+
+func TestAttachmentMarkup(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /nodes/5/attachments": `[
+			{"filename": "xss.PNG"},
+			{"filename": "request.txt"},
+			{"filename": "capture", "content_type": "image/jpeg"},
+			{"filename": "logo.png", "content_type": "application/octet-stream"},
+			{"filename": "burp request.xml"}
+		]`,
+	}))
+	node := Node{Id: 5, Project: &Project{Id: 1}}
+	attachments, err := gd.GetAllAttachments(&node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"!/pro/projects/1/nodes/5/attachments/xss.PNG!",
+		`"request.txt":/pro/projects/1/nodes/5/attachments/request.txt`,
+		"!/pro/projects/1/nodes/5/attachments/capture!",
+		`"logo.png":/pro/projects/1/nodes/5/attachments/logo.png`,
+		`"burp request.xml":/pro/projects/1/nodes/5/attachments/burp%20request.xml`,
+	}
+	if len(attachments) != len(want) {
+		t.Fatalf("got %v attachments, want %v", len(attachments), len(want))
+	}
+	for i, w := range want {
+		if got := AttachmentMarkup(&attachments[i]); got != w {
+			t.Errorf("%s: got %s, want %s", attachments[i].Filename, got, w)
+		}
+	}
+
+	// Without a Node reference the server's link is used
+	orphan := Attachment{Filename: "shot.png", Link: "/pro/projects/2/nodes/7/attachments/shot.png"}
+	if got := AttachmentMarkup(&orphan); got != "!/pro/projects/2/nodes/7/attachments/shot.png!" {
+		t.Errorf("got %s for an attachment without a node", got)
+	}
+}
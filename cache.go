@@ -0,0 +1,74 @@
+package godradis
+
+import "sync"
+
+// metadataCache holds server metadata that rarely changes, so that batch jobs don't look it up on every call
+type metadataCache struct {
+	mu sync.Mutex
+	noteCategories []NoteCategory
+	projectTemplates []ProjectTemplate
+}
+
+/*
+RefreshMetadata fetches the note categories and project templates from the server and replaces any cached copies. The
+cached accessors fetch the metadata on first use, so RefreshMetadata is only needed to pick up changes made on the server
+since then.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    err := gd.RefreshMetadata()
+ */
+func (gd *Godradis) RefreshMetadata() error {
+	categories, err := gd.GetAllNoteCategories()
+	if err != nil {
+		return err
+	}
+	templates, err := gd.GetAllProjectTemplates()
+	if err != nil {
+		return err
+	}
+	gd.metadata.mu.Lock()
+	defer gd.metadata.mu.Unlock()
+	gd.metadata.noteCategories = categories
+	gd.metadata.projectTemplates = templates
+	return nil
+}
+
+// InvalidateMetadata drops the cached metadata so that the next cached accessor call fetches it from the server again.
+func (gd *Godradis) InvalidateMetadata() {
+	gd.metadata.mu.Lock()
+	defer gd.metadata.mu.Unlock()
+	gd.metadata.noteCategories = nil
+	gd.metadata.projectTemplates = nil
+}
+
+// CachedNoteCategories returns the note categories, fetching them with GetAllNoteCategories only if they aren't cached.
+func (gd *Godradis) CachedNoteCategories() ([]NoteCategory, error) {
+	gd.metadata.mu.Lock()
+	defer gd.metadata.mu.Unlock()
+	if gd.metadata.noteCategories == nil {
+		categories, err := gd.GetAllNoteCategories()
+		if err != nil {
+			return []NoteCategory{}, err
+		}
+		gd.metadata.noteCategories = categories
+	}
+	return gd.metadata.noteCategories, nil
+}
+
+// CachedProjectTemplates returns the project templates, fetching them with GetAllProjectTemplates only if they aren't
+// cached.
+func (gd *Godradis) CachedProjectTemplates() ([]ProjectTemplate, error) {
+	gd.metadata.mu.Lock()
+	defer gd.metadata.mu.Unlock()
+	if gd.metadata.projectTemplates == nil {
+		templates, err := gd.GetAllProjectTemplates()
+		if err != nil {
+			return []ProjectTemplate{}, err
+		}
+		gd.metadata.projectTemplates = templates
+	}
+	return gd.metadata.projectTemplates, nil
+}
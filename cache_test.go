@@ -0,0 +1,55 @@
+package godradis
+
+import (
+	"testing"
+)
+
+func TestMetadataCache(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /categories": `[{"id": 1, "name": "Default category"}]`,
+		"GET /project_templates": `[]`,
+	})
+	gd, _ := newTestClient(t, fake)
+	count := func(route string) int {
+		n := 0
+		for _, r := range fake.requests {
+			if r == route {
+				n++
+			}
+		}
+		return n
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := gd.CachedNoteCategories(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gd.CachedProjectTemplates(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if count("GET /categories") != 1 || count("GET /project_templates") != 1 {
+		t.Errorf("got requests %v, want each endpoint hit once", fake.requests)
+	}
+
+	fake.routes["GET /categories"] = `[{"id": 1, "name": "Default category"}, {"id": 2, "name": "Hostnames"}]`
+	if categories, _ := gd.CachedNoteCategories(); len(categories) != 1 {
+		t.Errorf("got %v categories, want the cached list", len(categories))
+	}
+	if err := gd.RefreshMetadata(); err != nil {
+		t.Fatal(err)
+	}
+	if categories, _ := gd.CachedNoteCategories(); len(categories) != 2 {
+		t.Errorf("got %v categories after RefreshMetadata, want 2", len(categories))
+	}
+	if count("GET /categories") != 2 || count("GET /project_templates") != 2 {
+		t.Errorf("got requests %v after RefreshMetadata", fake.requests)
+	}
+
+	gd.InvalidateMetadata()
+	if _, err := gd.CachedNoteCategories(); err != nil {
+		t.Fatal(err)
+	}
+	if count("GET /categories") != 3 || count("GET /project_templates") != 2 {
+		t.Errorf("got requests %v after InvalidateMetadata", fake.requests)
+	}
+}
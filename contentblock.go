@@ -0,0 +1,48 @@
+package godradis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/iancoleman/orderedmap"
+	"github.com/pkg/errors"
+)
+
+type ContentBlock struct {
+	Id int `json:"id"`
+	Title string `json:"title"`
+	BlockGroup string `json:"block_group"`
+	Fields orderedmap.OrderedMap `json:"fields"`
+	Content string `json:"content"`
+	Project *Project
+}
+
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings, and the fields as either an object
+// or an array of key/value pairs.
+func (c *ContentBlock) UnmarshalJSON(data []byte) error {
+	type contentBlock ContentBlock
+	aux := struct {
+		*contentBlock
+		Id flexInt `json:"id"`
+		Fields flexFields `json:"fields"`
+	}{contentBlock: (*contentBlock)(c), Id: flexInt(c.Id), Fields: flexFields(c.Fields)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	c.Id = int(aux.Id)
+	c.Fields = orderedmap.OrderedMap(aux.Fields)
+	return nil
+}
+
+func (c *ContentBlock) GetField(key string) (string, error) {
+	value, ok := c.Fields.Get(key)
+	if !ok {
+		return "", errors.New(fmt.Sprintf("field not found: %v", key))
+	}
+	return value.(string), nil
+}
+
+// PlainContent returns the content block's field values in order, separated by blank lines, without the "#[Key]#" markers.
+func (c *ContentBlock) PlainContent() string {
+	return plainContent(&c.Fields, c.Content)
+}
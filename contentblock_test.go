@@ -0,0 +1,40 @@
+package godradis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetContentBlocksByGroup(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /content_blocks": `[
+			{"id": 1, "title": "Summary", "block_group": "Conclusions"},
+			{"id": 2, "title": "Scope", "block_group": "Introduction"},
+			{"id": 3, "title": "Next steps", "block_group": " Conclusions "},
+			{"id": 4, "title": "Disclaimer"},
+			{"id": 5, "title": "Thanks", "block_group": ""}
+		]`,
+	}))
+	project := Project{Id: 1}
+	groups, err := gd.GetContentBlocksByGroup(&project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := make(map[string][]string)
+	for group, blocks := range groups {
+		for _, block := range blocks {
+			titles[group] = append(titles[group], block.Title)
+			if block.Project != &project {
+				t.Errorf("block %v has no project reference", block.Id)
+			}
+		}
+	}
+	want := map[string][]string{
+		"Conclusions": {"Summary", "Next steps"},
+		"Introduction": {"Scope"},
+		"": {"Disclaimer", "Thanks"},
+	}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("got %v, want %v", titles, want)
+	}
+}
@@ -0,0 +1,53 @@
+package godradis
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+/*
+ExportIssuesCSV writes the project's issues to w as CSV, one row per issue. The first column is the issue ID and the rest
+are the union of the field names used by the issues, in the order they are first seen, so issues that don't have a field
+get an empty cell. Values containing commas, quotes or newlines are quoted.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    f, _ := os.Create("issues.csv")
+    defer f.Close()
+    err := gd.ExportIssuesCSV(&project, f)
+ */
+func (gd *Godradis) ExportIssuesCSV(project *Project, w io.Writer) error {
+	issues, err := gd.GetAllIssues(project)
+	if err != nil {
+		return err
+	}
+	keys := distinctFieldKeys(issues)
+
+	writer := csv.NewWriter(w)
+	err = writer.Write(append([]string{"Id"}, keys...))
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		row := []string{strconv.Itoa(issue.Id)}
+		for _, k := range keys {
+			v, ok := issue.Fields.Get(k)
+			if !ok {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, fmt.Sprintf("%v", v))
+		}
+		err = writer.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
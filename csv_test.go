@@ -0,0 +1,28 @@
+package godradis
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExportIssuesCSV(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /issues": `[
+			{"id": 1, "title": "XSS", "fields": {"Title": "XSS", "Severity": "High"}},
+			{"id": 2, "title": "SQLi", "fields": {"Title": "SQLi", "Description": "Found in id, name\r\nand \"q\""}},
+			{"id": 3, "title": "Banner", "fields": {"Severity": "Info"}}
+		]`,
+	}))
+	var buf bytes.Buffer
+	err := gd.ExportIssuesCSV(&Project{Id: 1}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Id,Title,Severity,Description\n" +
+		"1,XSS,High,\n" +
+		"2,SQLi,,\"Found in id, name\r\nand \"\"q\"\"\"\n" +
+		"3,,Info,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%q\nwant\n%q", got, want)
+	}
+}
@@ -1,9 +1,11 @@
 package godradis
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/iancoleman/orderedmap"
+	"strings"
 )
 
 type Evidence struct {
@@ -14,12 +16,45 @@ type Evidence struct {
 	Node *Node
 }
 
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings, and the fields as either an object
+// or an array of key/value pairs.
+func (e *Evidence) UnmarshalJSON(data []byte) error {
+	type evidence Evidence
+	aux := struct {
+		*evidence
+		Id flexInt `json:"id"`
+		Fields flexFields `json:"fields"`
+	}{evidence: (*evidence)(e), Id: flexInt(e.Id), Fields: flexFields(e.Fields)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	e.Id = int(aux.Id)
+	e.Fields = orderedmap.OrderedMap(aux.Fields)
+	return nil
+}
+
 type EvidenceIssue struct {
 	Id int `json:"id"`
 	Title string `json:"title"`
 	Url string `json:"url"`
 }
 
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings.
+func (e *EvidenceIssue) UnmarshalJSON(data []byte) error {
+	type evidenceIssue EvidenceIssue
+	aux := struct {
+		*evidenceIssue
+		Id flexInt `json:"id"`
+	}{evidenceIssue: (*evidenceIssue)(e), Id: flexInt(e.Id)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	e.Id = int(aux.Id)
+	return nil
+}
+
 func (e *Evidence) SetField(key, value string) {
 	e.Fields.Set(key, value)
 }
@@ -32,6 +67,11 @@ func (e *Evidence) GetField(key string) (string, error) {
 	return value.(string), nil
 }
 
+// PlainContent returns the evidence's field values in order, separated by blank lines, without the "#[Key]#" markers.
+func (e *Evidence) PlainContent() string {
+	return plainContent(&e.Fields, e.Content)
+}
+
 func (e *Evidence) CopyFields() orderedmap.OrderedMap {
 	fields := orderedmap.New()
 	keys := e.Fields.Keys()
@@ -42,4 +82,39 @@ func (e *Evidence) CopyFields() orderedmap.OrderedMap {
 		}
 	}
 	return *fields
-}
\ No newline at end of file
+}
+
+// IsReportable reports whether the evidence should appear in reports. Evidence is reportable unless its Reportable field
+// is "false", "no" or "0", compared case-insensitively, so evidence without the field is included.
+func (e *Evidence) IsReportable() bool {
+	value, ok := e.Fields.Get("Reportable")
+	if !ok {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(fmt.Sprintf("%v", value))) {
+	case "false", "no", "0":
+		return false
+	}
+	return true
+}
+
+// projectId returns the ID of the evidence's project, or an error if the evidence has no Node reference or its
+// Node has no Project reference
+func (e *Evidence) projectId() (int, error) {
+	if e.Node == nil {
+		return 0, errors.New(fmt.Sprintf("evidence %v has no Node reference", e.Id))
+	}
+	return e.Node.projectId()
+}
+
+/*
+EvidenceFromNote returns a copy of the note's fields, in the same order, ready to be passed to CreateEvidence to promote
+the note to evidence on an issue.
+
+    note, _ := gd.GetNoteByTitle(&node, "Weak SSH Ciphers")
+    evidence, _ := gd.CreateEvidence(&node, &issue, godradis.EvidenceFromNote(&note))
+ */
+func EvidenceFromNote(note *Note) *orderedmap.OrderedMap {
+	fields := note.CopyFields()
+	return &fields
+}
@@ -0,0 +1,309 @@
+package godradis
+
+import (
+	"fmt"
+	"github.com/iancoleman/orderedmap"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestApplyEvidenceTemplate(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"POST /nodes/1/evidence": `{"id": 11, "content": "#[Port]#\n443/tcp", "issue": {"id": 3}}`,
+		"POST /nodes/3/evidence": `{"id": 13, "content": "#[Port]#\n443/tcp", "issue": {"id": 3}}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	nodes := []*Node{{Id: 1, Project: &project}, {Id: 2, Project: &project}, {Id: 3, Project: &project}}
+	template := orderedmap.New()
+	template.Set("Port", "443/tcp")
+	evidences, err := gd.ApplyEvidenceTemplate(nodes, &Issue{Id: 3, Project: &project}, template)
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 nodes") || !strings.Contains(err.Error(), "node 2") {
+		t.Errorf("got error %v", err)
+	}
+	if len(evidences) != 2 || evidences[0].Node != nodes[0] || evidences[1].Node != nodes[2] {
+		t.Fatalf("got %v evidence", len(evidences))
+	}
+	for _, id := range []string{"1", "3"} {
+		body := fake.bodies["POST /nodes/"+id+"/evidence"]
+		if !strings.Contains(body, `"content":"#[Port]#\r\n443/tcp`) || !strings.Contains(body, `"issue_id":"3"`) {
+			t.Errorf("node %s: sent %s", id, body)
+		}
+	}
+}
+
+func TestGetEvidenceByContent(t *testing.T) {
+	node := Node{Evidence: []Evidence{
+		{Id: 1, Content: "#[Port]#\r\n80/tcp\r\n\r\n#[Output]#\r\nApache httpd 2.4"},
+		{Id: 2, Content: "#[Port]#\r\n443/tcp\r\n\r\n#[Output]#\r\nnginx"},
+		{Id: 3, Content: "#[Port]#\r\n8080/tcp\r\n\r\n#[Output]#\r\nAPACHE Tomcat"},
+	}}
+	tests := []struct {
+		substr string
+		want []int
+	}{
+		{"apache", []int{1, 3}},
+		{"NGINX", []int{2}},
+		{"/tcp", []int{1, 2, 3}},
+		{"iis", nil},
+	}
+	for _, tt := range tests {
+		var ids []int
+		for _, evidence := range node.GetEvidenceByContent(tt.substr) {
+			if evidence != &node.Evidence[evidence.Id-1] {
+				t.Errorf("%q: evidence %v is a copy", tt.substr, evidence.Id)
+			}
+			ids = append(ids, evidence.Id)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("%q: got evidence %v, want %v", tt.substr, ids, tt.want)
+		}
+	}
+}
+
+func TestResolveEvidenceIssue(t *testing.T) {
+	fake := newFakeDradis(map[string]string{"GET /issues/3": `{"id": 3, "title": "XSS", "fields": {"Title": "XSS"}}`})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	node := Node{Id: 2, Project: &project}
+	evidence := Evidence{Id: 7, Issue: EvidenceIssue{Id: 3}, Node: &node}
+	issue, err := gd.ResolveEvidenceIssue(&evidence)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Id != 3 || issue.Title != "XSS" || issue.Project != &project {
+		t.Errorf("got issue %v %q with project %p", issue.Id, issue.Title, issue.Project)
+	}
+
+	for _, e := range []*Evidence{{Id: 7, Issue: EvidenceIssue{Id: 3}}, {Id: 7, Issue: EvidenceIssue{Id: 3}, Node: &Node{Id: 2}}} {
+		if _, err := gd.ResolveEvidenceIssue(e); err == nil {
+			t.Errorf("expected an error resolving evidence with node %v", e.Node)
+		}
+	}
+	if n := len(fake.requests); n != 1 {
+		t.Errorf("got %v requests, want 1", n)
+	}
+}
+
+func TestEvidenceFromNote(t *testing.T) {
+	fake := newFakeDradis(map[string]string{"POST /nodes/2/evidence": `{"id": 8, "content": ""}`})
+	gd, _ := newTestClient(t, fake)
+	note := Note{Id: 5, Fields: *orderedmap.New()}
+	note.Fields.Set("Title", "Weak SSH Ciphers")
+	note.Fields.Set("Output", "arcfour")
+	note.Fields.Set("Port", "22/tcp")
+	fields := EvidenceFromNote(&note)
+	if keys := fields.Keys(); !reflect.DeepEqual(keys, []string{"Title", "Output", "Port"}) {
+		t.Errorf("got keys %v", keys)
+	}
+	// The copy is independent of the note
+	fields.Set("Output", "changed")
+	if output, _ := note.Fields.Get("Output"); output != "arcfour" {
+		t.Errorf("the note's Output was changed to %v", output)
+	}
+
+	node := Node{Id: 2, Project: &Project{Id: 1}}
+	issue := Issue{Id: 3, Project: node.Project}
+	if _, err := gd.CreateEvidence(&node, &issue, EvidenceFromNote(&note)); err != nil {
+		t.Fatal(err)
+	}
+	want := `#[Title]#\r\nWeak SSH Ciphers\r\n\r\n#[Output]#\r\narcfour\r\n\r\n#[Port]#\r\n22/tcp`
+	if body := fake.bodies["POST /nodes/2/evidence"]; !strings.Contains(body, want) {
+		t.Errorf("got body %s", body)
+	}
+}
+
+func TestAuditEvidenceReferences(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		// Issue 2 was deleted, but evidence 13 and 21 still point at it
+		"GET /issues": `[{"id": 1, "title": "XSS"}, {"id": 3, "title": "Unused"}]`,
+		"GET /nodes": testIssueEvidence,
+	}))
+	orphans, err := gd.AuditEvidenceReferences(&Project{Id: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, evidence := range orphans {
+		got = append(got, fmt.Sprintf("%v@%s->%v", evidence.Id, evidence.Node.Label, evidence.Issue.Id))
+	}
+	if want := []string{"13@10.0.0.1->2", "21@10.0.0.2->2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestEvidenceByNodeForIssue(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{"GET /nodes": testIssueEvidence}))
+	project := Project{Id: 1}
+	tests := []struct {
+		issueId int
+		want map[string][]int
+	}{
+		{1, map[string][]int{"10.0.0.1": {11, 12}}},
+		{2, map[string][]int{"10.0.0.1": {13}, "10.0.0.2": {21}}},
+		{3, map[string][]int{}},
+	}
+	for _, tt := range tests {
+		byNode, err := gd.EvidenceByNodeForIssue(&project, &Issue{Id: tt.issueId, Project: &project})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make(map[string][]int)
+		for label, evidences := range byNode {
+			for _, evidence := range evidences {
+				ids[label] = append(ids[label], evidence.Id)
+				if evidence.Node == nil || evidence.Node.Label != label {
+					t.Errorf("evidence %v has node %v under %v", evidence.Id, evidence.Node, label)
+				}
+			}
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("issue %v: got %v, want %v", tt.issueId, ids, tt.want)
+		}
+	}
+}
+
+func TestEvidenceAndNotesKeepCreationOrder(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		id := len(bodies)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if strings.HasSuffix(r.URL.Path, "/notes") {
+			fmt.Fprintf(w, `{"id": %v, "text": "#[Title]#\r\nNote"}`, id)
+			return
+		}
+		fmt.Fprintf(w, `{"id": %v, "content": "#[Port]#\r\n80", "issue": {"id": 3}}`, id)
+	}))
+	project := Project{Id: 1}
+	node := Node{Id: 2, Project: &project}
+	issue := Issue{Id: 3, Project: &project}
+	fields := orderedmap.New()
+	fields.Set("Port", "80")
+
+	for i := 0; i < 3; i++ {
+		if _, err := gd.CreateEvidence(&node, &issue, fields); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gd.CreateNote(&node, fields); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var evidenceIds, noteIds []int
+	for _, evidence := range node.Evidence {
+		evidenceIds = append(evidenceIds, evidence.Id)
+	}
+	for _, note := range node.Notes {
+		noteIds = append(noteIds, note.Id)
+	}
+	if !reflect.DeepEqual(evidenceIds, []int{1, 3, 5}) || !reflect.DeepEqual(noteIds, []int{2, 4, 6}) {
+		t.Errorf("got evidence %v and notes %v, want them in creation order", evidenceIds, noteIds)
+	}
+	// The API has no position for either, so none is sent
+	for _, body := range bodies {
+		if strings.Contains(body, "position") {
+			t.Errorf("sent a position in %s", body)
+		}
+	}
+}
+
+func TestCloneEvidenceToIssues(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/pro/api/nodes/2/evidence" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		id := 10 + len(bodies)
+		mu.Unlock()
+		if strings.Contains(string(body), `"issue_id":"9"`) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id": %v, "content": "#[Port]#\r\n443", "issue": {"id": %v}}`, id, id-6)
+	}))
+	project := Project{Id: 1}
+	node := Node{Id: 2, Project: &project}
+	evidence := Evidence{Id: 3, Content: "#[Port]#\r\n443", Node: &node, Issue: EvidenceIssue{Id: 4}}
+	node.Evidence = []Evidence{evidence}
+
+	clones, err := gd.CloneEvidenceToIssues(&evidence, []*Issue{{Id: 5, Project: &project}, {Id: 9, Project: &project},
+		{Id: 7, Project: &project}})
+	if err == nil || err.Error() != "could not clone evidence to 1 of 3 issues: issue 9: could not create evidence" {
+		t.Errorf("got error %v", err)
+	}
+	if len(clones) != 2 || clones[0].Issue.Id != 5 || clones[1].Issue.Id != 7 || clones[0].Node != &node {
+		t.Errorf("got clones %+v", clones)
+	}
+	var ids []int
+	for _, e := range node.Evidence {
+		ids = append(ids, e.Id)
+	}
+	if !reflect.DeepEqual(ids, []int{3, 11, 13}) {
+		t.Errorf("got node evidence %v, want the original and both clones", ids)
+	}
+	for i, issueId := range []string{"5", "9", "7"} {
+		want := fmt.Sprintf(`{"evidence":{"content":"#[Port]#\r\n443","issue_id":"%s"}}`, issueId)
+		if bodies[i] != want {
+			t.Errorf("got body %s, want %s", bodies[i], want)
+		}
+	}
+
+	if _, err = gd.CloneEvidenceToIssues(&Evidence{Id: 3}, []*Issue{{Id: 5, Project: &project}}); err == nil {
+		t.Error("expected an error cloning evidence without a Node reference")
+	}
+}
+
+func TestEvidenceBySeverity(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /issues": `[
+			{"id": 1, "title": "XSS", "fields": {"Title": "XSS", "Severity": " High "}},
+			{"id": 2, "title": "SQLi", "fields": {"Title": "SQLi", "Severity": "Critical"}},
+			{"id": 3, "title": "Banner", "fields": {"Title": "Banner"}},
+			{"id": 4, "title": "CSRF", "fields": {"Title": "CSRF", "Severity": "High"}}
+		]`,
+		"GET /nodes": `[
+			{"id": 1, "label": "10.0.0.1", "evidence": [
+				{"id": 11, "issue": {"id": 1}},
+				{"id": 12, "issue": {"id": 2}},
+				{"id": 13, "issue": {"id": 3}}
+			]},
+			{"id": 2, "label": "10.0.0.2", "evidence": [
+				{"id": 21, "issue": {"id": 4}},
+				{"id": 22, "issue": {"id": 99}}
+			]}
+		]`,
+	}))
+	bySeverity, err := gd.EvidenceBySeverity(&Project{Id: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make(map[string][]int)
+	for severity, evidences := range bySeverity {
+		for _, evidence := range evidences {
+			ids[severity] = append(ids[severity], evidence.Id)
+			if evidence.Node == nil {
+				t.Errorf("evidence %v has no Node reference", evidence.Id)
+			}
+		}
+	}
+	// Evidence for issue 3, which has no Severity, and for the deleted issue 99 is grouped under ""
+	want := map[string][]int{"High": {11, 21}, "Critical": {12}, "": {13, 22}}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("got %v, want %v", ids, want)
+	}
+}
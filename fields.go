@@ -0,0 +1,193 @@
+package godradis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/iancoleman/orderedmap"
+	"github.com/pkg/errors"
+	"regexp"
+	"strings"
+)
+
+// fieldMarker matches a "#[Key]#" field marker along with the line break that follows it
+var fieldMarker = regexp.MustCompile(`#\[[^\]]*\]#\r?\n?`)
+
+// liquidVariable matches a liquid output tag such as {{ issue.title }} and captures the variable name
+var liquidVariable = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+/*
+FieldFormat describes how an OrderedMap of fields is serialized into the body text sent to Dradis. Each field is written
+as KeyPrefix, the key, KeySuffix, ValueSeparator, the value and then FieldSeparator. Dradis only recognises fields marked
+with "#[" and "]#", so the prefix and suffix should only be changed for add-ons that parse the text themselves.
+
+    format := godradis.DefaultFieldFormat
+    format.FieldSeparator = "\n\n"
+    format.ValueSeparator = "\n"
+    gd.Config.FieldFormat = &format
+ */
+type FieldFormat struct {
+	KeyPrefix string `json:"key_prefix"`
+	KeySuffix string `json:"key_suffix"`
+	ValueSeparator string `json:"value_separator"`
+	FieldSeparator string `json:"field_separator"`
+}
+
+// DefaultFieldFormat is the "#[Key]#\r\nValue\r\n\r\n" layout used by the Dradis web interface.
+var DefaultFieldFormat = FieldFormat{
+	KeyPrefix: "#[",
+	KeySuffix: "]#",
+	ValueSeparator: "\r\n",
+	FieldSeparator: "\r\n\r\n",
+}
+
+// Format serializes fields into body text, in the map's key order.
+func (f FieldFormat) Format(fields *orderedmap.OrderedMap) string {
+	text := ""
+	for _, k := range fields.Keys() {
+		v, _ := fields.Get(k)
+		text += fmt.Sprintf("%s%v%s%s%v%s", f.KeyPrefix, k, f.KeySuffix, f.ValueSeparator, v, f.FieldSeparator)
+	}
+	return text
+}
+
+// flexFields decodes a "fields" payload sent either as a JSON object or, as some Dradis versions do, as an array of
+// {"key": ..., "value": ...} pairs. The field order is kept in both cases.
+type flexFields orderedmap.OrderedMap
+
+func (f *flexFields) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.Equal(trimmed, []byte("null")) {
+		return nil
+	}
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return (*orderedmap.OrderedMap)(f).UnmarshalJSON(data)
+	}
+	var pairs []struct {
+		Key string `json:"key"`
+		Value interface{} `json:"value"`
+	}
+	err := json.Unmarshal(data, &pairs)
+	if err != nil {
+		return err
+	}
+	fields := orderedmap.New()
+	for _, pair := range pairs {
+		fields.Set(pair.Key, pair.Value)
+	}
+	*f = flexFields(*fields)
+	return nil
+}
+
+/*
+ValidateFieldKeys returns an error if any key in fields is empty or only whitespace. Such a key is serialized as "#[]#",
+which Dradis doesn't treat as a field, so its value would end up appended to the previous field. The create and update
+methods call this automatically when Config.RejectEmptyFieldKeys is set.
+ */
+func ValidateFieldKeys(fields *orderedmap.OrderedMap) error {
+	for i, k := range fields.Keys() {
+		if strings.TrimSpace(k) == "" {
+			return errors.New(fmt.Sprintf("field %v has an empty key", i))
+		}
+	}
+	return nil
+}
+
+/*
+FieldsEqual reports whether a and b contain the same keys with the same values. If ordered is true the keys must also be
+in the same order, which matters because the order of the fields is the order they appear in the Dradis body.
+
+    evidence, _ := gd.GetEvidenceById(&node, 2)
+    fields := evidence.CopyFields()
+    fields.Set("Port", "995/tcp")
+    if !godradis.FieldsEqual(&evidence.Fields, &fields, false) {
+        _ := gd.UpdateEvidence(&evidence, &fields)
+    }
+ */
+func FieldsEqual(a, b *orderedmap.OrderedMap, ordered bool) bool {
+	return len(FieldsDiff(a, b, ordered)) == 0
+}
+
+// FieldsDiff returns the keys whose values differ between a and b, including keys that are only present in one of them.
+// If ordered is true, keys present in both that are in a different position relative to the other shared keys are also
+// returned. Keys from a come first in a's order, followed by keys only present in b in b's order.
+func FieldsDiff(a, b *orderedmap.OrderedMap, ordered bool) []string {
+	// Position of each shared key among the shared keys of b, so that keys only present in one map don't shift the rest
+	bPositions := make(map[string]int)
+	for _, k := range b.Keys() {
+		if _, ok := a.Get(k); ok {
+			bPositions[k] = len(bPositions)
+		}
+	}
+	var changed []string
+	position := 0
+	for _, k := range a.Keys() {
+		aValue, _ := a.Get(k)
+		bValue, ok := b.Get(k)
+		moved := ok && ordered && bPositions[k] != position
+		if ok {
+			position++
+		}
+		if !ok || moved || fmt.Sprintf("%v", aValue) != fmt.Sprintf("%v", bValue) {
+			changed = append(changed, k)
+		}
+	}
+	for _, k := range b.Keys() {
+		if _, ok := a.Get(k); !ok {
+			changed = append(changed, k)
+		}
+	}
+	return changed
+}
+
+/*
+RenderFieldsWithVars serializes fields into the Dradis "#[Key]#" body format, substituting liquid-style variables in the
+field values from vars. A variable is written as {{ name }}, with or without the inner spaces. Variables that have no
+entry in vars, and any other liquid tags such as {% for %} blocks, are left intact so Dradis can still render them.
+
+    fields := orderedmap.New()
+    fields.Set("Title", "Outdated software on {{ client }} hosts")
+    fields.Set("Description", "Found on {{ host }}, see {{ issue.references }}.")
+    text := godradis.RenderFieldsWithVars(fields, map[string]string{"client": "Foobar", "host": "10.0.0.1"})
+ */
+func RenderFieldsWithVars(fields *orderedmap.OrderedMap, vars map[string]string) string {
+	rendered := orderedmap.New()
+	for _, k := range fields.Keys() {
+		v, _ := fields.Get(k)
+		rendered.Set(k, substituteVars(fmt.Sprintf("%v", v), vars))
+	}
+	return parseOrderedMapFields(rendered)
+}
+
+func substituteVars(s string, vars map[string]string) string {
+	return liquidVariable.ReplaceAllStringFunc(s, func(match string) string {
+		name := liquidVariable.FindStringSubmatch(match)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// plainContent returns the values of fields joined by blank lines, skipping empty ones. If fields is empty, text is used
+// instead with its "#[Key]#" markers removed.
+func plainContent(fields *orderedmap.OrderedMap, text string) string {
+	var values []string
+	keys := fields.Keys()
+	if len(keys) == 0 {
+		values = fieldMarker.Split(text, -1)
+	} else {
+		for _, k := range keys {
+			v, _ := fields.Get(k)
+			values = append(values, fmt.Sprintf("%v", v))
+		}
+	}
+	var parts []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,272 @@
+package godradis
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/iancoleman/orderedmap"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFlexFields(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		keys []string
+		values []interface{}
+	}{
+		{"object", `{"fields": {"Title": "XSS", "CVSS": 6.1, "Tags": null}}`,
+			[]string{"Title", "CVSS", "Tags"}, []interface{}{"XSS", 6.1, nil}},
+		{"array", `{"fields": [{"key": "Title", "value": "XSS"}, {"key": "CVSS", "value": 6.1}]}`,
+			[]string{"Title", "CVSS"}, []interface{}{"XSS", 6.1}},
+		{"empty array", `{"fields": []}`, []string{}, []interface{}{}},
+		{"null", `{"fields": null}`, []string{}, []interface{}{}},
+	}
+	for _, tt := range tests {
+		var issue Issue
+		if err := json.Unmarshal([]byte(tt.json), &issue); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		keys := issue.Fields.Keys()
+		if keys == nil {
+			keys = []string{}
+		}
+		if !reflect.DeepEqual(keys, tt.keys) {
+			t.Errorf("%s: got keys %v, want %v", tt.name, keys, tt.keys)
+			continue
+		}
+		for i, key := range keys {
+			if value, _ := issue.Fields.Get(key); !reflect.DeepEqual(value, tt.values[i]) {
+				t.Errorf("%s: got %v = %v, want %v", tt.name, key, value, tt.values[i])
+			}
+		}
+	}
+}
+
+func TestFlexFieldsInvalid(t *testing.T) {
+	var evidence Evidence
+	if err := json.Unmarshal([]byte(`{"fields": [{"key": "Title", "value": "XSS"}, 3]}`), &evidence); err == nil {
+		t.Error("expected an error for a malformed array")
+	}
+}
+
+func TestFieldsDiff(t *testing.T) {
+	fields := func(pairs ...string) *orderedmap.OrderedMap {
+		m := orderedmap.New()
+		for i := 0; i < len(pairs); i += 2 {
+			m.Set(pairs[i], pairs[i+1])
+		}
+		return m
+	}
+	tests := []struct {
+		name string
+		a, b *orderedmap.OrderedMap
+		ordered bool
+		want []string
+	}{
+		{"equal", fields("Title", "XSS", "Port", "443"), fields("Title", "XSS", "Port", "443"), true, nil},
+		{"changed value", fields("Title", "XSS", "Port", "443"), fields("Title", "XSS", "Port", "80"), false, []string{"Port"}},
+		{"added and removed", fields("Title", "XSS", "Port", "443"), fields("Title", "XSS", "Host", "a"), false,
+			[]string{"Port", "Host"}},
+		{"reordered, unordered", fields("Title", "XSS", "Port", "443"), fields("Port", "443", "Title", "XSS"), false, nil},
+		{"reordered, ordered", fields("Title", "XSS", "Port", "443", "Host", "a"),
+			fields("Port", "443", "Title", "XSS", "Host", "a"), true, []string{"Title", "Port"}},
+		{"removed key doesn't shift order", fields("Title", "XSS", "Port", "443", "Host", "a"),
+			fields("Title", "XSS", "Host", "a"), true, []string{"Port"}},
+	}
+	for _, tt := range tests {
+		if got := FieldsDiff(tt.a, tt.b, tt.ordered); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		if got := FieldsEqual(tt.a, tt.b, tt.ordered); got != (len(tt.want) == 0) {
+			t.Errorf("%s: FieldsEqual returned %v", tt.name, got)
+		}
+	}
+}
+
+// dradisFieldMarker matches the start of a field the way Dradis splits issue text, including markers inside values
+var dradisFieldMarker = regexp.MustCompile(`#\[([^\]]+)\]#\r?\n`)
+
+// echoIssueFields answers POST /issues with an issue whose fields are parsed from the submitted text like Dradis does
+func echoIssueFields(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Issue struct {
+				Text string `json:"text"`
+			} `json:"issue"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		fields := orderedmap.New()
+		text := req.Issue.Text
+		matches := dradisFieldMarker.FindAllStringSubmatchIndex(text, -1)
+		for i, m := range matches {
+			end := len(text)
+			if i+1 < len(matches) {
+				end = matches[i+1][0]
+			}
+			fields.Set(text[m[2]:m[3]], strings.TrimRight(text[m[1]:end], "\r\n"))
+		}
+		body, _ := json.Marshal(map[string]interface{}{"id": 1, "title": "XSS", "fields": fields})
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}
+}
+
+func TestEscapeFieldValues(t *testing.T) {
+	fields := orderedmap.New()
+	fields.Set("Title", "XSS")
+	fields.Set("Description", "see #[Details]#\r\nbelow")
+	for _, escape := range []bool{false, true} {
+		gd, _ := newTestClient(t, echoIssueFields(t))
+		gd.Config.EscapeFieldValues = escape
+		issue, err := gd.CreateIssue(&Project{Id: 1}, fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		description, _ := issue.Fields.Get("Description")
+		got := UnescapeFieldValue(fmt.Sprintf("%v", description))
+		if escape && (len(issue.Fields.Keys()) != 2 || got != "see #[Details]#\r\nbelow") {
+			t.Errorf("got fields %v and description %q after escaping", issue.Fields.Keys(), got)
+		}
+		if !escape && len(issue.Fields.Keys()) != 3 {
+			t.Errorf("got fields %v, want the unescaped marker to split the description", issue.Fields.Keys())
+		}
+	}
+}
+
+func TestEscapeFieldValueRoundTrip(t *testing.T) {
+	for _, s := range []string{"", "plain", "#[Details]#", "a #[b]# c #[d"} {
+		if got := UnescapeFieldValue(EscapeFieldValue(s)); got != s {
+			t.Errorf("got %q after a round trip of %q", got, s)
+		}
+		if strings.Contains(EscapeFieldValue(s), "#[") {
+			t.Errorf("escaped %q still contains a field marker", s)
+		}
+	}
+}
+
+func TestRenderFieldsWithVars(t *testing.T) {
+	fields := orderedmap.New()
+	fields.Set("Title", "Outdated software on {{ client }} hosts")
+	fields.Set("Description", "Found on {{host}}, see {{ issue.references }}.{% if x %}!{% endif %}")
+	got := RenderFieldsWithVars(fields, map[string]string{"client": "Foobar", "host": "10.0.0.1"})
+	want := "#[Title]#\r\nOutdated software on Foobar hosts\r\n\r\n" +
+		"#[Description]#\r\nFound on 10.0.0.1, see {{ issue.references }}.{% if x %}!{% endif %}\r\n\r\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if value, _ := fields.Get("Title"); value != "Outdated software on {{ client }} hosts" {
+		t.Errorf("the fields were changed to %v", value)
+	}
+}
+
+func TestFieldFormat(t *testing.T) {
+	fields := orderedmap.New()
+	fields.Set("Title", "XSS")
+	fields.Set("CVSS", 6.1)
+	if got, want := DefaultFieldFormat.Format(fields), "#[Title]#\r\nXSS\r\n\r\n#[CVSS]#\r\n6.1\r\n\r\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	format := DefaultFieldFormat
+	format.ValueSeparator = "\n"
+	format.FieldSeparator = "\n\n"
+	fake := newFakeDradis(map[string]string{"POST /issues": `{"id": 1, "title": "XSS"}`})
+	gd, _ := newTestClient(t, fake)
+	if err := WithFieldFormat(format)(&gd.Config); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gd.CreateIssue(&Project{Id: 1}, fields); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"issue":{"text":"#[Title]#\nXSS\n\n#[CVSS]#\n6.1\n\n"}}`
+	if body := fake.bodies["POST /issues"]; body != want {
+		t.Errorf("got body %s, want %s", body, want)
+	}
+	if err := WithFieldFormat(FieldFormat{KeySuffix: "]#"})(&gd.Config); err == nil {
+		t.Error("expected an error for a format without a key prefix")
+	}
+}
+
+func TestRejectEmptyFieldKeys(t *testing.T) {
+	for _, key := range []string{"", "  \t"} {
+		fields := orderedmap.New()
+		fields.Set("Title", "XSS")
+		fields.Set(key, "orphaned value")
+		if err := ValidateFieldKeys(fields); err == nil || err.Error() != "field 1 has an empty key" {
+			t.Errorf("key %q: got error %v", key, err)
+		}
+
+		fake := newFakeDradis(map[string]string{"POST /issues": `{"id": 1, "title": "XSS"}`})
+		gd, _ := newTestClient(t, fake)
+		gd.Config.RejectEmptyFieldKeys = true
+		if _, err := gd.CreateIssue(&Project{Id: 1}, fields); err == nil {
+			t.Errorf("key %q: expected an error", key)
+		}
+		if sent := fake.sent(); len(sent) != 0 {
+			t.Errorf("key %q: got requests %v, want none", key, sent)
+		}
+
+		// Lenient by default, the key is sent as is
+		gd.Config.RejectEmptyFieldKeys = false
+		if _, err := gd.CreateIssue(&Project{Id: 1}, fields); err != nil {
+			t.Fatal(err)
+		}
+		if body := fake.bodies["POST /issues"]; !strings.Contains(body, fmt.Sprintf(`#[%s]#`, strings.Replace(key, "\t", `\t`, -1))) {
+			t.Errorf("key %q: got body %s", key, body)
+		}
+	}
+
+	fields := orderedmap.New()
+	fields.Set("Title", "XSS")
+	if err := ValidateFieldKeys(fields); err != nil {
+		t.Errorf("got error %v for valid keys", err)
+	}
+}
+
+func TestPlainContent(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /issues/1": `{"id": 1, "title": "XSS", "text": "#[Title]#\r\nXSS\r\n\r\n#[Severity]#\r\nHigh",
+			"fields": {"Title": "XSS", "Severity": "High"}}`,
+		"GET /nodes/2/evidence/3": `{"id": 3, "content": "#[Port]#\r\n443\r\n\r\n#[Output]#\r\n  HTTP/1.1 200 OK  \r\n\r\n#[Notes]#\r\n",
+			"fields": {"Port": "443", "Output": "  HTTP/1.1 200 OK  ", "Notes": ""}}`,
+		"GET /nodes/2/notes/4": `{"id": 4, "text": "#[Title]#\r\nNmap\r\n\r\n#[Output]#\r\n22/tcp open\r\n80/tcp open"}`,
+	}))
+	project := Project{Id: 1}
+	node := Node{Id: 2, Project: &project}
+	issue, err := gd.GetIssueById(&project, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evidence, err := gd.GetEvidenceById(&node, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, err := gd.GetNoteById(&node, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got string
+		want string
+	}{
+		{"issue", issue.PlainContent(), "XSS\n\nHigh"},
+		// Values are trimmed and empty ones are left out
+		{"evidence", evidence.PlainContent(), "443\n\nHTTP/1.1 200 OK"},
+		// Without parsed fields the markers are stripped from the text
+		{"note", note.PlainContent(), "Nmap\n\n22/tcp open\r\n80/tcp open"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package godradis
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+// flexInt decodes an integer sent either as a JSON number or as a numeric string. Dradis has used both forms for IDs
+// across versions, so the structs decode their ID fields through it. An empty string or null decodes as 0.
+type flexInt int
+
+func (f *flexInt) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		err := json.Unmarshal(data, &s)
+		if err != nil {
+			return err
+		}
+		if s == "" {
+			*f = 0
+			return nil
+		}
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		// Returned as a type error so that callers decoding lists can skip the bad item like any other type mismatch
+		return &json.UnmarshalTypeError{Value: "string " + strconv.Quote(s), Type: reflect.TypeOf(0)}
+	}
+	*f = flexInt(i)
+	return nil
+}
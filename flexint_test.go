@@ -0,0 +1,64 @@
+package godradis
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var flexIntTests = []struct {
+	json string
+	want int
+	wantErr bool
+}{
+	{`7`, 7, false},
+	{`"7"`, 7, false},
+	{`""`, 0, false},
+	{`null`, 0, false},
+	{`"seven"`, 0, true},
+}
+
+func TestProjectUnmarshalFlexIds(t *testing.T) {
+	for _, tt := range flexIntTests {
+		var project Project
+		err := json.Unmarshal([]byte(`{"id": `+tt.json+`, "name": "Foobar"}`), &project)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("id %s: got error %v", tt.json, err)
+			continue
+		}
+		if !tt.wantErr && (project.Id != tt.want || project.Name != "Foobar") {
+			t.Errorf("id %s: got %+v", tt.json, project)
+		}
+	}
+}
+
+func TestNodeUnmarshalFlexIds(t *testing.T) {
+	for _, tt := range flexIntTests {
+		var node Node
+		data := `{"id": ` + tt.json + `, "label": "10.0.0.1", "type_id": ` + tt.json + `, "parent_id": ` + tt.json + `, "position": ` + tt.json + `}`
+		err := json.Unmarshal([]byte(data), &node)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("id %s: got error %v", tt.json, err)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if node.Id != tt.want || int(node.TypeId) != tt.want || node.ParentId != tt.want || node.Position != tt.want || node.Label != "10.0.0.1" {
+			t.Errorf("id %s: got id %v, type %v, parent %v, position %v", tt.json, node.Id, node.TypeId, node.ParentId, node.Position)
+		}
+	}
+}
+
+func TestIssueUnmarshalFlexIds(t *testing.T) {
+	for _, tt := range flexIntTests {
+		var issue Issue
+		err := json.Unmarshal([]byte(`{"id": `+tt.json+`, "title": "XSS"}`), &issue)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("id %s: got error %v", tt.json, err)
+			continue
+		}
+		if !tt.wantErr && (issue.Id != tt.want || issue.Title != "XSS") {
+			t.Errorf("id %s: got %+v", tt.json, issue)
+		}
+	}
+}
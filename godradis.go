@@ -3,6 +3,7 @@ package godradis
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -15,13 +16,20 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 type Godradis struct {
 	Config Config
 	httpClient http.Client
+	metadata metadataCache
+	deletedProjects sync.Map // IDs of the projects removed with DeleteProject
+	limiter rateLimiter
+	authMu sync.Mutex // Guards Config.ApiKey once the client is in use, so that ReauthFunc runs once per expired key
 }
 
 // Configuration
@@ -30,6 +38,27 @@ type Config struct {
 	BaseUrl string `json:"dradis_url"`
 	ApiKey string `json:"api_key"`
 	Verify bool `json:"verify"`
+	EscapeFieldValues bool `json:"escape_field_values"` // Apply EscapeFieldValue to values passed as an OrderedMap
+	ReconnectOnError bool `json:"reconnect_on_error"` // Close idle connections after a transport error and resend once, except POST
+	StrictTemplates bool `json:"strict_templates"` // Reject CreateProject template names missing from GetAllProjectTemplates
+	// Connection pool limits passed to the http.Transport. Zero leaves the net/http default in place.
+	MaxIdleConns int `json:"max_idle_conns"`
+	MaxIdleConnsPerHost int `json:"max_idle_conns_per_host"`
+	MaxConnsPerHost int `json:"max_conns_per_host"`
+	Timeout time.Duration `json:"timeout"` // Per-request timeout in nanoseconds. Zero means no timeout.
+	Proxy string `json:"proxy"` // URL of an HTTP proxy to send requests through
+	RateLimit float64 `json:"rate_limit"` // Maximum requests per second, see WithRateLimit. Zero means no limit.
+	MaxRetries int `json:"max_retries"` // Number of times to retry a failed request, see WithRetry
+	RejectEmptyFieldKeys bool `json:"reject_empty_field_keys"` // Check OrderedMap fields with ValidateFieldKeys before sending them
+	CaseSensitiveNames bool `json:"case_sensitive_names"` // Match names, labels and titles exactly in the By-Name lookups
+	StrictDeletes bool `json:"strict_deletes"` // Treat a 200 response to a delete as a failure if its body reports an error
+	// TLS settings passed to the http.Transport. Zero values leave Go's defaults in place. CipherSuites only applies up to TLS 1.2.
+	MinTLSVersion uint16 `json:"min_tls_version"` // e.g. tls.VersionTLS12
+	CipherSuites []uint16 `json:"cipher_suites"`
+	// ReauthFunc, if set, is called when a request gets a 401 response. It returns a new API key, which replaces ApiKey,
+	// and the request is sent once more with it. Concurrent requests rejected with the same key share a single call.
+	ReauthFunc func() (string, error) `json:"-"`
+	FieldFormat *FieldFormat `json:"field_format"` // Layout of the body text built from an OrderedMap. nil means DefaultFieldFormat.
 }
 
 /*
@@ -39,14 +68,40 @@ the API key shown on the user's Dradis profile page. verify instructs godradis w
 Dradis server.
 
 After creating the configuration, Configure creates an http.client on the Godradis object to be used for all subsequent
-HTTP requests to the Dradis server.
+HTTP requests to the Dradis server. Other Config fields, such as the connection pool limits, are left as they are, so they
+can be set on gd.Config before calling Configure.
 
     gd := godradis.Godradis{}
     gd.Configure("https://example.com", "abcdefghijk", false)
  */
 func (gd *Godradis) Configure(url, apiKey string, verify bool) {
-	gd.Config = Config{url, apiKey, verify}
-	gd.createClient(verify)
+	// WithVerify can't fail, so neither can Configure
+	_ = gd.ConfigureWithOptions(url, apiKey, WithVerify(verify))
+}
+
+/*
+ConfigureWithOptions behaves like Configure but takes functional options for the rest of the configuration instead of a
+fixed set of arguments. TLS certificates are verified unless WithVerify(false) is passed. An error is returned if any
+option is invalid, in which case the client is not created.
+
+    gd := godradis.Godradis{}
+    err := gd.ConfigureWithOptions("https://example.com", "abcdefghijk",
+        godradis.WithTimeout(30 * time.Second),
+        godradis.WithProxy("http://127.0.0.1:8080"),
+        godradis.WithRetry(3))
+ */
+func (gd *Godradis) ConfigureWithOptions(baseUrl, apiKey string, opts ...Option) error {
+	gd.Config.BaseUrl = baseUrl
+	gd.Config.ApiKey = apiKey
+	gd.Config.Verify = true
+	for _, opt := range opts {
+		err := opt(&gd.Config)
+		if err != nil {
+			return err
+		}
+	}
+	gd.createClient(gd.Config.Verify)
+	return nil
 }
 
 /*
@@ -65,8 +120,32 @@ func (gd *Godradis) LoadConfig(filename string) error {
 		return err
 	}
 	defer file.Close()
-	fileBytes, _ := ioutil.ReadAll(file)
-	err = json.Unmarshal(fileBytes, &gd.Config)
+	return gd.LoadConfigFromReader(file)
+}
+
+/*
+LoadConfigFromReader behaves like LoadConfig but reads the JSON configuration from r, e.g. a secret fetched from a vault,
+instead of a file.
+
+    gd := godradis.Godradis{}
+    err := gd.LoadConfigFromReader(strings.NewReader(`{"dradis_url": "https://example.com", "api_key": "abcdefghijk"}`))
+ */
+func (gd *Godradis) LoadConfigFromReader(r io.Reader) error {
+	configBytes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return gd.LoadConfigFromBytes(configBytes)
+}
+
+/*
+LoadConfigFromBytes behaves like LoadConfig but takes the JSON configuration directly, e.g. from an embedded file.
+
+    gd := godradis.Godradis{}
+    err := gd.LoadConfigFromBytes(configJson)
+ */
+func (gd *Godradis) LoadConfigFromBytes(b []byte) error {
+	err := json.Unmarshal(b, &gd.Config)
 	if err != nil {
 		return err
 	}
@@ -78,47 +157,440 @@ func (gd *Godradis) LoadConfig(filename string) error {
 
 func (gd *Godradis) createClient(verify bool) {
 	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: !verify},
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: !verify,
+			MinVersion: gd.Config.MinTLSVersion,
+			CipherSuites: gd.Config.CipherSuites,
+		},
+		MaxIdleConns: gd.Config.MaxIdleConns,
+		MaxIdleConnsPerHost: gd.Config.MaxIdleConnsPerHost,
+		MaxConnsPerHost: gd.Config.MaxConnsPerHost,
+	}
+
+	if gd.Config.Proxy != "" {
+		// The URL is checked by WithProxy; an invalid one from a config file is ignored rather than failing every request
+		proxyUrl, err := url.Parse(gd.Config.Proxy)
+		if err == nil {
+			tr.Proxy = http.ProxyURL(proxyUrl)
+		}
 	}
 
-	gd.httpClient = http.Client{Transport: tr}
+	gd.httpClient = http.Client{Transport: tr, Timeout: gd.Config.Timeout}
 }
 
-func (gd *Godradis) sendRequest(method, resource string, body []byte) (*http.Response, error) {
-	req, _ := http.NewRequest(method, fmt.Sprintf("%s/pro/api/%s", gd.Config.BaseUrl, resource), bytes.NewBuffer(body))
-	req.Header.Add("Authorization", fmt.Sprintf(`Token token="%s"`, gd.Config.ApiKey))
-	if method == "DELETE" || ((method == "POST" || method == "PUT") && body != nil) {
-		req.Header.Set("Content-Type", "application/json")
-	}
-	resp, err := gd.httpClient.Do(req)
+var (
+	// ErrUnauthorized is returned when the server answers with an HTML page instead of JSON, which Dradis does when it
+	// redirects to the login page because the API key is invalid or the session has expired.
+	ErrUnauthorized = errors.New("unauthorized: the server returned an HTML page instead of JSON, check the API key")
+	// ErrUnexpectedContentType is returned when a successful response has a content type other than JSON or HTML.
+	ErrUnexpectedContentType = errors.New("unexpected content type in server response")
+)
+
+// retryBackoff is multiplied by the attempt number to get the delay before each retry
+const retryBackoff = 500 * time.Millisecond
+
+// doRequest sends req with the configured http.Client, retrying if configured to, and rejects successful responses that
+// don't contain JSON
+func (gd *Godradis) doRequest(req *http.Request) (*http.Response, error) {
+	resp, err := gd.doRequestWithRetries(req)
 	if err != nil {
 		return resp, err
 	}
+	if err = checkContentType(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
 	return resp, nil
 }
 
-func (gd *Godradis) sendRequestWithProjectId(method, resource string, projectId int, body []byte) (*http.Response, error) {
-	req, _ := http.NewRequest(method, fmt.Sprintf("%s/pro/api/%s", gd.Config.BaseUrl, resource), bytes.NewBuffer(body))
-	req.Header.Add("Authorization", fmt.Sprintf(`Token token="%s"`, gd.Config.ApiKey))
+// send waits for the rate limiter and sends req once with the configured http.Client
+func (gd *Godradis) send(req *http.Request) (*http.Response, error) {
+	if err := gd.limiter.wait(req.Context(), gd.Config.RateLimit); err != nil {
+		return nil, err
+	}
+	return gd.httpClient.Do(req)
+}
+
+// doRequestWithRetries sends req with the configured http.Client, reconnecting and retrying if configured to, without
+// looking at the response's content type
+func (gd *Godradis) doRequestWithRetries(req *http.Request) (*http.Response, error) {
+	resp, err := gd.send(req)
+	if err != nil && gd.Config.ReconnectOnError {
+		gd.httpClient.CloseIdleConnections()
+		// Like shouldRetry, a POST isn't resent after a transport error since its object may already have been created
+		if req.Method != "POST" {
+			resp, err = gd.resend(req)
+		}
+	}
+	// A cancelled request context fails every attempt, so there is no point retrying
+	for attempt := 1; attempt <= gd.Config.MaxRetries && shouldRetry(req, resp, err) && req.Context().Err() == nil; attempt++ {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-time.After(time.Duration(attempt) * retryBackoff):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if err = rewindBody(req); err != nil {
+			return nil, err
+		}
+		resp, err = gd.send(req)
+	}
+	// Requests sent without the API key, e.g. to another host, are never given one
+	authenticated := req.Header.Get("Authorization") != ""
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && gd.Config.ReauthFunc != nil && authenticated {
+		return gd.reauthAndRetry(req, resp)
+	}
+	return resp, err
+}
+
+// reauthAndRetry replaces the API key with the one returned by Config.ReauthFunc and sends req once more with it. resp is
+// the 401 response, which is closed.
+func (gd *Godradis) reauthAndRetry(req *http.Request, resp *http.Response) (*http.Response, error) {
+	resp.Body.Close()
+	apiKey, err := gd.refreshApiKey(req.Header.Get("Authorization"))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not refresh API key after 401 response")
+	}
+	req.Header.Set("Authorization", authHeader(apiKey))
+	if err = rewindBody(req); err != nil {
+		return nil, err
+	}
+	return gd.send(req)
+}
+
+// refreshApiKey calls Config.ReauthFunc and stores the key it returns. If the key has already been replaced since sentAuth
+// was built, by another request that got a 401 at the same time, the current key is returned without calling ReauthFunc
+// again.
+func (gd *Godradis) refreshApiKey(sentAuth string) (string, error) {
+	gd.authMu.Lock()
+	defer gd.authMu.Unlock()
+	if sentAuth != authHeader(gd.Config.ApiKey) {
+		return gd.Config.ApiKey, nil
+	}
+	apiKey, err := gd.Config.ReauthFunc()
+	if err != nil {
+		return "", err
+	}
+	gd.Config.ApiKey = apiKey
+	return apiKey, nil
+}
+
+// apiKey returns Config.ApiKey, which ReauthFunc may replace while requests are in flight
+func (gd *Godradis) apiKey() string {
+	gd.authMu.Lock()
+	defer gd.authMu.Unlock()
+	return gd.Config.ApiKey
+}
+
+func authHeader(apiKey string) string {
+	return fmt.Sprintf(`Token token="%s"`, apiKey)
+}
+
+// shouldRetry reports whether req can be sent again after resp or err. Other methods are retried after a transport error
+// or a 429/502/503/504, but a POST may already have created its object by the time the connection fails or a gateway
+// gives up, so it is only retried after a 429 or 503, which the server sends before doing anything.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Method == "POST" {
+		return err == nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable)
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// rewindBody resets req.Body so that the request can be sent again
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+// resend sends req again after a transport error. ReconnectOnError doesn't recreate the http.Client, since other requests
+// may be using it; closing its idle connections beforehand is enough to make the retry open a new connection in place of
+// any the server has reset.
+func (gd *Godradis) resend(req *http.Request) (*http.Response, error) {
+	if err := rewindBody(req); err != nil {
+		return nil, err
+	}
+	return gd.send(req)
+}
+
+func checkContentType(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil
+	}
+	contentType := strings.ToLower(resp.Header.Get("Content-Type"))
+	if contentType == "" || strings.Contains(contentType, "json") {
+		return nil
+	}
+	if strings.HasPrefix(contentType, "text/html") {
+		return ErrUnauthorized
+	}
+	return errors.Wrap(ErrUnexpectedContentType, contentType)
+}
+
+// checkDelete turns the response to a DELETE request into an error. With Config.StrictDeletes set, a 200 response is also
+// rejected if its body reports an error, since some Dradis versions answer 200 for objects that don't exist. A
+// successful delete has a body like {"message":"Resource deleted successfully"}.
+func (gd *Godradis) checkDelete(resp *http.Response, errMsg string) error {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(errMsg)
+	}
+	if !gd.Config.StrictDeletes {
+		return nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var result map[string]interface{}
+	if json.Unmarshal(body, &result) != nil {
+		// Empty or non-object bodies carry no error message
+		return nil
+	}
+	for _, key := range []string{"error", "errors"} {
+		if v, ok := result[key]; ok {
+			return errors.New(fmt.Sprintf("%s: %v", errMsg, v))
+		}
+	}
+	if message, ok := result["message"].(string); ok && !strings.Contains(strings.ToLower(message), "success") {
+		return errors.New(fmt.Sprintf("%s: %s", errMsg, message))
+	}
+	return nil
+}
+
+// newRequest builds an authenticated request for resource. GetBody is always set so that the body can be sent again if
+// the request has to be retried.
+func (gd *Godradis) newRequest(method, resource string, body []byte) *http.Request {
+	req, _ := http.NewRequest(method, fmt.Sprintf("%s/pro/api/%s", gd.Config.BaseUrl, resource), bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Header.Add("Authorization", authHeader(gd.apiKey()))
 	if method == "DELETE" || ((method == "POST" || method == "PUT") && body != nil) {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	return req
+}
+
+func (gd *Godradis) sendRequest(method, resource string, body []byte) (*http.Response, error) {
+	req := gd.newRequest(method, resource, body)
+	return gd.doRequest(req)
+}
+
+func (gd *Godradis) sendRequestWithProjectId(method, resource string, projectId int, body []byte) (*http.Response, error) {
+	if err := gd.checkProjectDeleted(projectId); err != nil {
+		return nil, err
+	}
+	req := gd.newRequest(method, resource, body)
 	req.Header.Set("Dradis-Project-Id", strconv.Itoa(projectId))
-	resp, err := gd.httpClient.Do(req)
+	return gd.doRequest(req)
+}
+
+// ErrProjectDeleted is returned by project-scoped calls for a project that was deleted with DeleteProject on this client.
+var ErrProjectDeleted = errors.New("project has been deleted")
+
+// checkProjectDeleted returns ErrProjectDeleted if the project was deleted with DeleteProject, so that calls through stale
+// Project pointers fail clearly instead of with a 404
+func (gd *Godradis) checkProjectDeleted(projectId int) error {
+	if _, ok := gd.deletedProjects.Load(projectId); ok {
+		return errors.Wrapf(ErrProjectDeleted, "project %v", projectId)
+	}
+	return nil
+}
+
+// ErrConflict is returned by the conditional update methods when the object was changed on the server after it was fetched.
+var ErrConflict = errors.New("conflict: the object was modified on the server since it was fetched")
+
+// maxConcurrentRequests is the number of requests the concurrent helpers have in flight at the same time
+const maxConcurrentRequests = 4
+
+// ErrStopIteration can be returned from an iterator callback to stop iterating early. The iterator then returns nil.
+var ErrStopIteration = errors.New("stop iteration")
+
+// getPage fetches a single page of a list endpoint along with the response headers. projectId is omitted from the request
+// if it is 0.
+func (gd *Godradis) getPage(resource string, projectId, page int) ([]byte, http.Header, error) {
+	var resp *http.Response
+	var err error
+	if projectId == 0 {
+		resp, err = gd.sendRequest("GET", fmt.Sprintf("%s?page=%v", resource, page), nil)
+	} else {
+		resp, err = gd.sendRequestWithProjectId("GET", fmt.Sprintf("%s?page=%v", resource, page), projectId, nil)
+	}
 	if err != nil {
-		return resp, err
+		return nil, nil, err
 	}
-	return resp, nil
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, errors.New(fmt.Sprintf("could not get page %v of %s", page, resource))
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, resp.Header, nil
 }
 
-func parseOrderedMapFields(fields *orderedmap.OrderedMap) string {
-	text := ""
-	keys := fields.Keys()
-	for _, k := range keys {
+/*
+eachPage requests resource one page at a time and calls fn with each page's body and headers. fn must call isNew with the
+ID of every item it decodes and skip the items for which it returns false. Paging stops once a page holds no new items,
+which guards against servers that ignore the page parameter and return the full list every time, or when fn returns an
+error. ErrStopIteration stops paging without an error.
+ */
+func (gd *Godradis) eachPage(resource string, projectId int, fn func(body []byte, header http.Header, isNew func(id int) bool) error) error {
+	seen := make(map[int]bool)
+	for page := 1; ; page++ {
+		body, header, err := gd.getPage(resource, projectId, page)
+		if err != nil {
+			return err
+		}
+		newItems := 0
+		err = fn(body, header, func(id int) bool {
+			if seen[id] {
+				return false
+			}
+			seen[id] = true
+			newItems++
+			return true
+		})
+		if err == ErrStopIteration {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if newItems == 0 {
+			return nil
+		}
+	}
+}
+
+// countResource returns the number of items in a list endpoint. The total is read from the X-Total-Count or Total header
+// if the server sends one, otherwise every page is fetched and only the item IDs are decoded.
+func (gd *Godradis) countResource(resource string, projectId int) (int, error) {
+	count := 0
+	err := gd.eachPage(resource, projectId, func(body []byte, header http.Header, isNew func(int) bool) error {
+		if count == 0 {
+			if total, ok := headerInt(header, "X-Total-Count", "Total"); ok {
+				count = total
+				return ErrStopIteration
+			}
+		}
+		var items []struct {
+			Id int `json:"id"`
+		}
+		err := json.Unmarshal(body, &items)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if isNew(item.Id) {
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+/*
+EscapeFieldValue makes s safe to use as a field value by replacing every "#[" with "#&#91;". Dradis starts a new field at
+any "#[" in the content, so an unescaped value such as "see #[Details]#" would otherwise be split into two fields. The
+entity renders as "[" in Dradis reports, but the escaped form is what the server stores and returns, so values read back
+from Fields need to be passed through UnescapeFieldValue to recover the original string.
+
+Escaping is applied automatically by the OrderedMap-based create and update methods when Config.EscapeFieldValues is set.
+ */
+func EscapeFieldValue(s string) string {
+	return strings.Replace(s, "#[", "#&#91;", -1)
+}
+
+// UnescapeFieldValue reverses EscapeFieldValue.
+func UnescapeFieldValue(s string) string {
+	return strings.Replace(s, "#&#91;", "#[", -1)
+}
+
+// parseFields converts fields to body text in the configured FieldFormat, escaping the values first if the client is
+// configured to do so. An error is returned if the client is configured to reject empty field keys and fields has one.
+func (gd *Godradis) parseFields(fields *orderedmap.OrderedMap) (string, error) {
+	if gd.Config.RejectEmptyFieldKeys {
+		err := ValidateFieldKeys(fields)
+		if err != nil {
+			return "", err
+		}
+	}
+	format := DefaultFieldFormat
+	if gd.Config.FieldFormat != nil {
+		format = *gd.Config.FieldFormat
+	}
+	if !gd.Config.EscapeFieldValues {
+		return format.Format(fields), nil
+	}
+	escaped := orderedmap.New()
+	for _, k := range fields.Keys() {
 		v, _ := fields.Get(k)
-		text += fmt.Sprintf("#[%v]#\r\n%v\r\n\r\n", k, v)
+		escaped.Set(k, EscapeFieldValue(fmt.Sprintf("%v", v)))
+	}
+	return format.Format(escaped), nil
+}
+
+// namesMatch compares a name, label or title from the server with the one being looked up, ignoring case unless the
+// client is configured to match case-sensitively
+func (gd *Godradis) namesMatch(a, b string) bool {
+	if gd.Config.CaseSensitiveNames {
+		return a == b
+	}
+	return strings.ToLower(a) == strings.ToLower(b)
+}
+
+// nameContains reports whether a name, label or title from the server contains substr, with the same case handling as
+// namesMatch
+func (gd *Godradis) nameContains(name, substr string) bool {
+	if gd.Config.CaseSensitiveNames {
+		return strings.Contains(name, substr)
+	}
+	return strings.Contains(strings.ToLower(name), strings.ToLower(substr))
+}
+
+// parseTimestamp parses the RFC 3339 timestamps used in the created_at and updated_at properties
+func parseTimestamp(timestamp string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "could not parse timestamp %s", timestamp)
+	}
+	return t, nil
+}
+
+// sameTimestamp compares two timestamps as points in time, falling back to comparing the strings if either can't be parsed
+func sameTimestamp(a, b string) bool {
+	aTime, aErr := parseTimestamp(a)
+	bTime, bErr := parseTimestamp(b)
+	if aErr != nil || bErr != nil {
+		return a == b
 	}
-	return text
+	return aTime.Equal(bTime)
+}
+
+func parseOrderedMapFields(fields *orderedmap.OrderedMap) string {
+	return DefaultFieldFormat.Format(fields)
 }
 
 // Projects Endpoint
@@ -158,6 +630,60 @@ func (gd *Godradis) GetAllProjects() ([]Project, error) {
 	return projects, nil
 }
 
+/*
+GetAllProjectsPartial behaves like GetAllProjects except that the list is decoded one project at a time, so a record that
+can't be decoded doesn't hide the rest. Projects with a mismatched property type are skipped and decoding carries on;
+malformed JSON stops decoding at that point. Everything decoded successfully is returned along with an error describing
+what went wrong, which is nil if the whole list was decoded.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    projectList, err := gd.GetAllProjectsPartial()
+    if err != nil {
+        fmt.Printf("decoded %v projects before error: %v", len(projectList), err)
+    }
+ */
+func (gd *Godradis) GetAllProjectsPartial() ([]Project, error) {
+	resp, err := gd.sendRequest("GET", "projects", nil)
+	if err != nil {
+		return []Project{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return []Project{}, errors.New("could not get projects from server")
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	token, err := decoder.Token()
+	if err != nil {
+		return []Project{}, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return []Project{}, errors.New("could not decode projects: response is not a list")
+	}
+	projects := []Project{}
+	var failures []string
+	for i := 0; decoder.More(); i++ {
+		var project Project
+		err = decoder.Decode(&project)
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			failures = append(failures, fmt.Sprintf("project %v: %v", i, err))
+			continue
+		}
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("project %v: %v", i, err))
+			break
+		}
+		projects = append(projects, project)
+	}
+	if len(failures) > 0 {
+		return projects, errors.New(fmt.Sprintf("could not decode all projects: %s", strings.Join(failures, "; ")))
+	}
+	return projects, nil
+}
+
 /*
 GetProjectById fetches a Project object from the Dradis server based on the int id.
 
@@ -192,6 +718,96 @@ func (gd *Godradis) GetProjectById(id int) (Project, error) {
 	return project, nil
 }
 
+/*
+GetProjectsByIds fetches the projects with the given ids concurrently, a few at a time, and returns them in the same order
+as ids. Projects that can't be fetched are left out of the result and described in the returned error.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    projects, err := gd.GetProjectsByIds([]int{45, 46, 47})
+ */
+func (gd *Godradis) GetProjectsByIds(ids []int) ([]Project, error) {
+	projects := make([]Project, len(ids))
+	errs := make([]error, len(ids))
+	sem := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i, id int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			projects[i], errs[i] = gd.GetProjectById(id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	results := []Project{}
+	var failures []string
+	for i, id := range ids {
+		if errs[i] != nil {
+			failures = append(failures, fmt.Sprintf("project %v: %v", id, errs[i]))
+			continue
+		}
+		results = append(results, projects[i])
+	}
+	if len(failures) > 0 {
+		return results, errors.New(fmt.Sprintf("could not get %v of %v projects: %s", len(failures), len(ids), strings.Join(failures, "; ")))
+	}
+	return results, nil
+}
+
+/*
+GetAllProjectsWithIssueCounts returns every project on the server along with the number of issues in it. The project
+payload doesn't include a count, so each project's issues are counted with CountIssues, a few projects at a time. Projects
+whose issues can't be counted are left out of the result and described in the returned error.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    counts, _ := gd.GetAllProjectsWithIssueCounts()
+    for _, pc := range counts {
+        fmt.Printf("%s: %v issues\n", pc.Project.Name, pc.IssueCount)
+    }
+ */
+func (gd *Godradis) GetAllProjectsWithIssueCounts() ([]ProjectIssueCount, error) {
+	projects, err := gd.GetAllProjects()
+	if err != nil {
+		return []ProjectIssueCount{}, err
+	}
+	counts := make([]int, len(projects))
+	errs := make([]error, len(projects))
+	sem := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i := range projects {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			counts[i], errs[i] = gd.CountIssues(&projects[i])
+		}(i)
+	}
+	wg.Wait()
+
+	results := []ProjectIssueCount{}
+	var failures []string
+	for i := range projects {
+		if errs[i] != nil {
+			failures = append(failures, fmt.Sprintf("project %v: %v", projects[i].Id, errs[i]))
+			continue
+		}
+		results = append(results, ProjectIssueCount{Project: projects[i], IssueCount: counts[i]})
+	}
+	if len(failures) > 0 {
+		return results, errors.New(fmt.Sprintf("could not count issues in %v of %v projects: %s", len(failures), len(projects), strings.Join(failures, "; ")))
+	}
+	return results, nil
+}
+
 /*
 GetProjectByName searches for and returns a Project object based on the name. GetProjectByName works by calling GetAllProjects
 first and then ranges over them comparing the name strings.
@@ -211,27 +827,55 @@ func (gd *Godradis) GetProjectByName(name string) (Project, error) {
 		return Project{}, err
 	}
 	for _, project := range projects {
-		if strings.ToLower(project.Name) == strings.ToLower(name) {
+		if gd.namesMatch(project.Name, name) {
 			return project, nil
 		}
 	}
 	return Project{}, errors.New(fmt.Sprintf("Could not find project %s", name))
 }
 
-type projectDetails struct {
-	Name string `json:"name,omitempty"`
-	ClientId int `json:"team_id,omitempty"` // For some reason, POST/PUT methods use strings instead of ints even though they return ints
-	ReportTemplatePropertiesId int `json:"report_template_properties_id,omitempty"`
-	AuthorIds []int `json:"author_ids,omitempty"`
-	Template string `json:"template,omitempty"`
-}
+/*
+GetProjectsUpdatedSince returns every project whose UpdatedAt timestamp is after t. The Projects endpoint has no
+server-side filter, so all projects are fetched with GetAllProjects and filtered locally.
 
-func (pd *projectDetails) parseArguments(name, clientId, reportTemplatePropertiesId interface{}, authorIds []int, template interface{}) {
-	if name == nil {
-		pd.Name = ""
-	} else {
-		pd.Name = name.(string)
-	}
+    gd := godradis.Godradis{}
+
+    [...]
+
+    projects, _ := gd.GetProjectsUpdatedSince(time.Now().Add(-24 * time.Hour))
+ */
+func (gd *Godradis) GetProjectsUpdatedSince(t time.Time) ([]Project, error) {
+	projects, err := gd.GetAllProjects()
+	if err != nil {
+		return []Project{}, err
+	}
+	updated := []Project{}
+	for _, project := range projects {
+		updatedAt, err := project.UpdatedTime()
+		if err != nil {
+			return []Project{}, err
+		}
+		if updatedAt.After(t) {
+			updated = append(updated, project)
+		}
+	}
+	return updated, nil
+}
+
+type projectDetails struct {
+	Name string `json:"name,omitempty"`
+	ClientId int `json:"team_id,omitempty"` // For some reason, POST/PUT methods use strings instead of ints even though they return ints
+	ReportTemplatePropertiesId int `json:"report_template_properties_id,omitempty"`
+	AuthorIds []int `json:"author_ids,omitempty"`
+	Template string `json:"template,omitempty"`
+}
+
+func (pd *projectDetails) parseArguments(name, clientId, reportTemplatePropertiesId interface{}, authorIds []int, template interface{}) error {
+	if name == nil {
+		pd.Name = ""
+	} else {
+		pd.Name = name.(string)
+	}
 	if clientId == nil {
 		pd.ClientId = 0
 	} else {
@@ -243,18 +887,32 @@ func (pd *projectDetails) parseArguments(name, clientId, reportTemplatePropertie
 		pd.ReportTemplatePropertiesId = reportTemplatePropertiesId.(int)
 	}
 	pd.AuthorIds = authorIds
-	if template == nil {
+	switch t := template.(type) {
+	case nil:
 		pd.Template = ""
-	} else {
-		pd.Template = template.(string)
+	case ProjectTemplate:
+		pd.Template = t.Name
+	case *ProjectTemplate:
+		if t != nil {
+			pd.Template = t.Name
+		}
+	case string:
+		pd.Template = t
+	default:
+		return errors.New(fmt.Sprintf("template must be a string or a ProjectTemplate, not %T", template))
 	}
+	return nil
 }
 
 /*
 CreateProject creates a project on the Dradis server and returns the newly created Project object. All 5 arguments are
 required in the function call, but only name and clientId must be non-nil. reportTemplatePropertiesId is an optional int
 that assigns a default report template to the project. authorIds accepts an int slice of authors to assign to the project.
-template is an optional string that assigns the project template based on the template name.
+template is optional and assigns the project template, either by name as a string or as a ProjectTemplate from
+GetAllProjectTemplates. The API selects templates by name only, so the name is first looked up with
+CachedProjectTemplates and an error is returned if more than one template has it, rather than letting the server pick one.
+A ProjectTemplate must also match a listed template by both ID and name. A string name that isn't listed, or a failure
+to list the templates, is only an error if Config.StrictTemplates is set.
 
     gd := godradis.Godradis{}
 
@@ -271,7 +929,23 @@ func (gd *Godradis) CreateProject(name string, clientId int, reportTemplatePrope
 	}
 
 	pd := projectDetails{}
-	pd.parseArguments(name, clientId, reportTemplatePropertiesId, authorIds, template)
+	err := pd.parseArguments(name, clientId, reportTemplatePropertiesId, authorIds, template)
+	if err != nil {
+		return Project{}, err
+	}
+	if pd.Template != "" {
+		templateId := 0
+		switch t := template.(type) {
+		case ProjectTemplate:
+			templateId = t.Id
+		case *ProjectTemplate:
+			templateId = t.Id
+		}
+		err = gd.validateProjectTemplate(pd.Template, templateId)
+		if err != nil {
+			return Project{}, err
+		}
+	}
 
 	jsonBody, err := json.Marshal(&reqModel{pd})
 	if err != nil {
@@ -283,6 +957,9 @@ func (gd *Godradis) CreateProject(name string, clientId int, reportTemplatePrope
 	}
 	defer resp.Body.Close()
 	var newProject Project
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return Project{}, parseValidationError(resp)
+	}
 	if resp.StatusCode != http.StatusCreated {
 		return Project{}, errors.New("could not create project")
 	}
@@ -299,6 +976,76 @@ func (gd *Godradis) CreateProject(name string, clientId int, reportTemplatePrope
 	return newProject, nil
 }
 
+/*
+GetAllProjectTemplates takes no arguments and returns a list of the project templates on the server. The template names
+are the values accepted by the template argument of CreateProject.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    templates, _ := gd.GetAllProjectTemplates()
+    for _, template := range templates {
+        fmt.Println(template.Name)
+    }
+ */
+func (gd *Godradis) GetAllProjectTemplates() ([]ProjectTemplate, error) {
+	resp, err := gd.sendRequest("GET", "project_templates", nil)
+	if err != nil {
+		return []ProjectTemplate{}, err
+	}
+	defer resp.Body.Close()
+	var templates []ProjectTemplate
+	if resp.StatusCode != http.StatusOK {
+		return []ProjectTemplate{}, errors.New("could not get project template list")
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return []ProjectTemplate{}, err
+	}
+
+	err = json.Unmarshal(body, &templates)
+	if err != nil {
+		return []ProjectTemplate{}, err
+	}
+	return templates, nil
+}
+
+// validateProjectTemplate looks name up in the project template listing. A name shared by several templates is always
+// rejected, since the server selects templates by name and could apply the wrong one. If id is non-zero, as it is for a
+// ProjectTemplate, it must be the template with that name. Otherwise an unknown name, or a failure to list the templates,
+// is only an error with Config.StrictTemplates set.
+func (gd *Godradis) validateProjectTemplate(name string, id int) error {
+	strict := gd.Config.StrictTemplates || id != 0
+	templates, err := gd.CachedProjectTemplates()
+	if err != nil {
+		if strict {
+			return err
+		}
+		return nil
+	}
+	var names []string
+	var matches []string
+	matchesId := false
+	for _, template := range templates {
+		if template.Name == name {
+			matches = append(matches, strconv.Itoa(template.Id))
+			matchesId = matchesId || template.Id == id
+		}
+		names = append(names, template.Name)
+	}
+	switch {
+	case len(matches) == 0 && strict:
+		return errors.New(fmt.Sprintf("unknown project template %s (available: %s)", name, strings.Join(names, ", ")))
+	case len(matches) > 1:
+		// The server looks templates up by name, so there is no way to say which of these is meant
+		return errors.New(fmt.Sprintf("ambiguous project template %s (matches templates with ids %s)", name, strings.Join(matches, ", ")))
+	case len(matches) == 1 && id != 0 && !matchesId:
+		return errors.New(fmt.Sprintf("project template %v is not named %s", id, name))
+	}
+	return nil
+}
+
 /*
 UpdateProject takes a reference to an existing Project object as well as 5 arguments representing properties to update.
 All arguments are required to be passed to UpdateProject but only properties being modified need to be non-nil. UpdateProject
@@ -321,7 +1068,10 @@ func (gd *Godradis) UpdateProject(p *Project, name, clientId, reportTemplateProp
 	}
 
 	pd := projectDetails{}
-	pd.parseArguments(name, clientId, reportTemplatePropertiesId, authorIds, template)
+	err := pd.parseArguments(name, clientId, reportTemplatePropertiesId, authorIds, template)
+	if err != nil {
+		return err
+	}
 
 	jsonBody, err := json.Marshal(&reqModel{pd})
 	if err != nil {
@@ -332,6 +1082,9 @@ func (gd *Godradis) UpdateProject(p *Project, name, clientId, reportTemplateProp
 		return err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return parseValidationError(resp)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return errors.New("could not update project")
 	}
@@ -347,7 +1100,9 @@ func (gd *Godradis) UpdateProject(p *Project, name, clientId, reportTemplateProp
 }
 
 /*
-DeleteProject takes a reference to a Project object and deletes the project on the Dradis server.
+DeleteProject takes a reference to a Project object and deletes the project on the Dradis server. On success p.Deleted is
+set, and any later project-scoped call on this client for the project, such as through a Node or Issue that still points
+to it, returns ErrProjectDeleted without contacting the server.
 
     gd := godradis.Godradis{}
 
@@ -364,43 +1119,132 @@ func (gd *Godradis) DeleteProject(p *Project) error {
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode == http.StatusOK {
-		return nil
-	} else {
-		return errors.New("could not delete project.")
+	err = gd.checkDelete(resp, "could not delete project.")
+	if err != nil {
+		return err
 	}
+	p.Deleted = true
+	gd.deletedProjects.Store(p.Id, true)
+	return nil
 }
 
-// Teams endpoint
+/*
+GetProjectAuthorIds resolves the author emails on a Project object to user IDs using GetAllUsers. The Projects endpoint
+only returns author emails, but CreateProject and UpdateProject take author IDs, so this is needed to pass the current
+authors back to the server. An error is returned if any author email does not match a user.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectById(45)
+    authorIds, _ := gd.GetProjectAuthorIds(&project)
+ */
+func (gd *Godradis) GetProjectAuthorIds(p *Project) ([]int, error) {
+	users, err := gd.GetAllUsers()
+	if err != nil {
+		return []int{}, err
+	}
+	idsByEmail := make(map[string]int, len(users))
+	for _, user := range users {
+		idsByEmail[strings.ToLower(user.Email)] = user.Id
+	}
+	authorIds := make([]int, 0, len(p.Authors))
+	for _, author := range p.Authors {
+		id, ok := idsByEmail[strings.ToLower(author.Email)]
+		if !ok {
+			return []int{}, errors.New(fmt.Sprintf("could not find user for author %s", author.Email))
+		}
+		authorIds = append(authorIds, id)
+	}
+	return authorIds, nil
+}
 
 /*
-GetAllTeams takes no arguments and returns a list of all teams on the server.
+UpdateProjectPreservingAuthors behaves like UpdateProject but sends the project's current authors along with the update,
+resolved to IDs with GetProjectAuthorIds, so that updating an unrelated property doesn't risk dropping the authors.
 
     gd := godradis.Godradis{}
 
     [...]
 
-    teamList, _ := gd.GetAllTeams()
-    if len(teamList) > 0 {
-        fmt.Printf("%v", teamList[0].Name)
+    project, _ := gd.GetProjectById(45)
+    err := gd.UpdateProjectPreservingAuthors(&project, "Modified the project name", nil, nil, nil)
+ */
+func (gd *Godradis) UpdateProjectPreservingAuthors(p *Project, name, clientId, reportTemplatePropertiesId, template interface{}) error {
+	authorIds, err := gd.GetProjectAuthorIds(p)
+	if err != nil {
+		return err
+	}
+	return gd.UpdateProject(p, name, clientId, reportTemplatePropertiesId, authorIds, template)
+}
+
+// Users endpoint
+
+/*
+GetAllUsers takes no arguments and returns a list of all users on the server.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    users, _ := gd.GetAllUsers()
+    for _, user := range users {
+        fmt.Printf("%v: %v\n", user.Id, user.Email)
     }
  */
-func (gd *Godradis) GetAllTeams() ([]Team, error) {
-	resp, err := gd.sendRequest("GET", "teams", nil)
+func (gd *Godradis) GetAllUsers() ([]User, error) {
+	resp, err := gd.sendRequest("GET", "users", nil)
 	if err != nil {
-		return []Team{}, err
+		return []User{}, err
 	}
 	defer resp.Body.Close()
-	var teams []Team
+	var users []User
 	if resp.StatusCode != http.StatusOK {
-		return []Team{}, errors.New("could not get teams list")
+		return []User{}, errors.New("could not get users list")
 	}
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return []Team{}, err
+		return []User{}, err
+	}
+
+	err = json.Unmarshal(body, &users)
+	if err != nil {
+		return []User{}, err
 	}
+	return users, nil
+}
+
+// Teams endpoint
 
-	err = json.Unmarshal(body, &teams)
+/*
+GetAllTeams takes no arguments and returns a list of all teams on the server. Teams are requested one page at a time
+until the server stops returning new teams, so the full list is returned even on servers that paginate the endpoint.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    teamList, _ := gd.GetAllTeams()
+    if len(teamList) > 0 {
+        fmt.Printf("%v", teamList[0].Name)
+    }
+ */
+func (gd *Godradis) GetAllTeams() ([]Team, error) {
+	var teams []Team
+	err := gd.eachPage("teams", 0, func(body []byte, _ http.Header, isNew func(int) bool) error {
+		var pageTeams []Team
+		err := json.Unmarshal(body, &pageTeams)
+		if err != nil {
+			return err
+		}
+		for _, team := range pageTeams {
+			if isNew(team.Id) {
+				teams = append(teams, team)
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return []Team{}, err
 	}
@@ -460,13 +1304,67 @@ func (gd *Godradis) GetTeamByName(name string) (Team, error) {
 		return Team{}, err
 	}
 	for _, team := range teams {
-		if strings.ToLower(team.Name) == strings.ToLower(name) {
+		if gd.namesMatch(team.Name, name) {
 			return team, nil
 		}
 	}
 	return Team{}, errors.New(fmt.Sprintf("could not find team with name %s", name))
 }
 
+/*
+GetTeamsByName returns every team whose name contains substr, compared case-insensitively unless
+Config.CaseSensitiveNames is set. An empty slice is returned if no team matches.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    teams, _ := gd.GetTeamsByName("client")
+    for _, team := range teams {
+        fmt.Println(team.Name)
+    }
+ */
+func (gd *Godradis) GetTeamsByName(substr string) ([]Team, error) {
+	teams, err := gd.GetAllTeams()
+	if err != nil {
+		return []Team{}, err
+	}
+	matches := []Team{}
+	for _, team := range teams {
+		if gd.nameContains(team.Name, substr) {
+			matches = append(matches, team)
+		}
+	}
+	return matches, nil
+}
+
+/*
+GetClientByName returns the Client whose name matches name, compared case-insensitively unless Config.CaseSensitiveNames
+is set. Clients are Dradis teams, so the Client's Id is the clientId that CreateProject expects.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    client, err := gd.GetClientByName("Test Client")
+    if err != nil {
+        fmt.Println(err)
+    }
+    project, _ := gd.CreateProject("Foobar External Network Penetration Test", client.Id, nil, nil, nil)
+ */
+func (gd *Godradis) GetClientByName(name string) (Client, error) {
+	teams, err := gd.GetAllTeams()
+	if err != nil {
+		return Client{}, err
+	}
+	for _, team := range teams {
+		if gd.namesMatch(team.Name, name) {
+			return Client{Id: team.Id, Name: team.Name}, nil
+		}
+	}
+	return Client{}, errors.New(fmt.Sprintf("could not find client with name %s", name))
+}
+
 type teamDetails struct {
 	Name string `json:"name,omitempty"`
 	TeamSince string `json:"team_since,omitempty"`
@@ -517,6 +1415,9 @@ func (gd *Godradis) CreateTeam(name string, teamSince ...string) (Team, error) {
 	}
 	defer resp.Body.Close()
 	var newTeam Team
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return Team{}, parseValidationError(resp)
+	}
 	if resp.StatusCode != http.StatusCreated {
 		return Team{}, errors.New("could not create team")
 	}
@@ -559,6 +1460,9 @@ func (gd *Godradis) UpdateTeam(t *Team, name, teamSince interface{}) error {
 		return err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return parseValidationError(resp)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return errors.New("could not update team")
 	}
@@ -591,17 +1495,15 @@ func (gd *Godradis) DeleteTeam(t *Team) error {
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode == http.StatusOK {
-		return nil
-	} else {
-		return errors.New("could not delete team")
-	}
+	return gd.checkDelete(resp, "could not delete team")
 }
 
 // Nodes endpoint
 
 /*
 GetAllNodes takes a reference to a Project object and returns a list of all Nodes that exist on the server for that project.
+By default each node's Evidence and Notes are populated; the WithEvidence and WithNotes options can leave either out. If
+both are left out the nodes are fetched with GetAllNodesShallow.
 
     gd := godradis.Godradis{}
 
@@ -609,8 +1511,17 @@ GetAllNodes takes a reference to a Project object and returns a list of all Node
 
     project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
     nodes, _ := gd.GetAllNodes(&project)
+    nodesWithoutNotes, _ := gd.GetAllNodes(&project, godradis.WithNotes(false))
  */
-func (gd *Godradis) GetAllNodes(project *Project) ([]Node, error) {
+func (gd *Godradis) GetAllNodes(project *Project, opts ...NodeOption) ([]Node, error) {
+	options := nodeOptions{evidence: true, notes: true}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if !options.evidence && !options.notes {
+		return gd.GetAllNodesShallow(project)
+	}
+
 	resp, err := gd.sendRequestWithProjectId("GET", "nodes", project.Id, nil)
 	if err != nil {
 		return []Node{}, err
@@ -631,122 +1542,447 @@ func (gd *Godradis) GetAllNodes(project *Project) ([]Node, error) {
 	}
 	for i := 0; i < len(nodes); i++ {
 		nodes[i].Project = project
-		nodes[i].setEvidenceNodeReferences()
-		nodes[i].setNoteNodeReferences()
+		if options.evidence {
+			nodes[i].setEvidenceNodeReferences()
+		} else {
+			nodes[i].Evidence = nil
+		}
+		if options.notes {
+			nodes[i].setNoteNodeReferences()
+		} else {
+			nodes[i].Notes = nil
+		}
 	}
 	return nodes, nil
 }
 
 /*
-GetNodeById takes a reference to a Project object and int id and returns the node associated with that id.
+GetAllNodesShallow behaves like GetAllNodes except that the evidence and notes embedded in the server's response are not
+decoded and no back-references are wired, which is considerably faster on large projects when only node properties such
+as the label are needed. The returned nodes have nil Evidence and Notes, so in-memory helpers like GetEvidenceById and
+GetNotesByTitle will find nothing on them; use GetAllEvidence and GetAllNotes to load those separately.
 
     gd := godradis.Godradis{}
 
     [...]
 
     project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
-    node, _ := gd.GetNodeById(&project, 7)
+    nodes, _ := gd.GetAllNodesShallow(&project)
  */
-func (gd *Godradis) GetNodeById(project *Project, id int) (Node, error) {
-	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v", id), project.Id, nil)
+func (gd *Godradis) GetAllNodesShallow(project *Project) ([]Node, error) {
+	// Only the scalar node properties, so that json.Unmarshal() skips the evidence and notes arrays
+	type shallowNode struct {
+		Id int `json:"id"`
+		Label string `json:"label"`
+		TypeId NodeType `json:"type_id"`
+		ParentId int `json:"parent_id"`
+		Position int `json:"position"`
+		CreatedAt string `json:"created_at"`
+		UpdatedAt string `json:"updated_at"`
+	}
+
+	resp, err := gd.sendRequestWithProjectId("GET", "nodes", project.Id, nil)
 	if err != nil {
-		return Node{}, err
+		return []Node{}, err
 	}
 	defer resp.Body.Close()
-	var node Node
+	var shallowNodes []shallowNode
 	if resp.StatusCode != http.StatusOK {
-		return Node{}, errors.New("could not get node")
+		return []Node{}, errors.New("could not get nodes list")
 	}
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return Node{}, err
+		return []Node{}, err
 	}
 
-	err = json.Unmarshal(body, &node)
+	err = json.Unmarshal(body, &shallowNodes)
 	if err != nil {
-		return Node{}, err
+		return []Node{}, err
 	}
-	node.Project = project
-	node.setEvidenceNodeReferences()
-	node.setNoteNodeReferences()
-	return node, nil
+	nodes := make([]Node, len(shallowNodes))
+	for i, sn := range shallowNodes {
+		nodes[i].Id = sn.Id
+		nodes[i].Label = sn.Label
+		nodes[i].TypeId = sn.TypeId
+		nodes[i].ParentId = sn.ParentId
+		nodes[i].Position = sn.Position
+		nodes[i].CreatedAt = sn.CreatedAt
+		nodes[i].UpdatedAt = sn.UpdatedAt
+		nodes[i].Project = project
+	}
+	return nodes, nil
 }
 
 /*
-GetNodeByLabel searches for and returns a Node object based on the label. GetNodeByLabel works by calling GetAllNodes
-first and then ranges over them comparing the label strings.
+GetNodesPage takes a reference to a Project object and a page number (starting at 1) and returns a single page of the
+project's nodes along with the pagination metadata reported by the server, e.g. for showing progress while paging
+through a large project.
 
     gd := godradis.Godradis{}
 
     [...]
 
     project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
-    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
+    nodesPage, _ := gd.GetNodesPage(&project, 1)
+    fmt.Printf("%v of %v nodes", len(nodesPage.Items), nodesPage.TotalCount)
  */
-func (gd *Godradis) GetNodeByLabel(project *Project, label string) (Node, error) {
-	nodes, err := gd.GetAllNodes(project)
+func (gd *Godradis) GetNodesPage(project *Project, page int) (NodesPage, error) {
+	body, header, err := gd.getPage("nodes", project.Id, page)
 	if err != nil {
-		return Node{}, err
-	}
-	for _, node := range nodes {
-		if strings.ToLower(node.Label) == strings.ToLower(label) {
-			return node, nil
-		}
-	}
-	return Node{}, errors.New(fmt.Sprintf("could not find node with label %s", label))
-}
-
-type nodeDetails struct {
-	Label string `json:"label,omitempty"`
-	TypeId int `json:"type_id,omitempty"`
-	ParentId int `json:"parent_id,omitempty"`
-	Position int `json:"position,omitempty"`
-}
-
-func (nd *nodeDetails) parseArguments(label, typeId, parentId, position interface{}) {
-	if label == nil {
-		nd.Label = ""
-	} else {
-		nd.Label = label.(string)
+		return NodesPage{}, err
 	}
-	if typeId == nil {
-		nd.TypeId = 0
-	} else {
-		nd.TypeId = typeId.(int)
-	}
-	if parentId == nil {
-		nd.ParentId = 0
-	} else {
-		nd.ParentId = parentId.(int)
+	var nodes []Node
+	err = json.Unmarshal(body, &nodes)
+	if err != nil {
+		return NodesPage{}, err
 	}
-	if position == nil {
-		nd.Position = 0
-	} else {
-		nd.Position = position.(int)
+	for i := 0; i < len(nodes); i++ {
+		nodes[i].Project = project
+		nodes[i].setEvidenceNodeReferences()
+		nodes[i].setNoteNodeReferences()
 	}
+	return NodesPage{parseListResult(header, page), nodes}, nil
 }
 
 /*
-CreateNode takes a reference to a Project object and several mandatory properties and creates a new Node on the server
-and returns it. label is a string representing the name of the node. typeId is an int and can be 0 (a "default" node) or
-1 (a "host" node). parentId is an int indicating the ID of the parent node if there is one, otherwise it will be created
-as a top-level node. position is an int that determines where to insert the node within the existing node structure.
+NodesIterator takes a reference to a Project object and a callback and calls the callback once for every node in the
+project, requesting the nodes from the server one page at a time instead of loading the full list into memory. Iteration
+stops at the first error returned by the callback, which is then returned by NodesIterator, unless the error is
+ErrStopIteration, in which case NodesIterator returns nil.
 
     gd := godradis.Godradis{}
 
     [...]
 
     project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
-    node, _ := gd.CreateNode(&project, "127.0.0.1", 1, 14, 3)
+    err := gd.NodesIterator(&project, func(node *godradis.Node) error {
+        fmt.Println(node.Label)
+        return nil
+    })
  */
-func (gd *Godradis) CreateNode(project *Project, label string, typeId int, parentId int, position int) (Node, error) {
-	// BUG(njfox): The parentId argument to CreateNode may not be correctly serialized in the API request
-
-	// Required so that json.Marshal() sends the fields wrapped in a node{} json object
-	type reqModel struct {
-		Node nodeDetails `json:"node"`
+func (gd *Godradis) NodesIterator(project *Project, fn func(*Node) error) error {
+	return gd.eachPage("nodes", project.Id, func(body []byte, _ http.Header, isNew func(int) bool) error {
+		var nodes []Node
+		err := json.Unmarshal(body, &nodes)
+		if err != nil {
+			return err
+		}
+		for i := range nodes {
+			if !isNew(nodes[i].Id) {
+				continue
+			}
+			nodes[i].Project = project
+			nodes[i].setEvidenceNodeReferences()
+			nodes[i].setNoteNodeReferences()
+			err = fn(&nodes[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+/*
+CountNodes takes a reference to a Project object and returns the number of nodes in the project without decoding the full
+node list. See CountIssues.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    count, _ := gd.CountNodes(&project)
+ */
+func (gd *Godradis) CountNodes(project *Project) (int, error) {
+	return gd.countResource("nodes", project.Id)
+}
+
+/*
+GetEmptyNodes takes a reference to a Project object and returns references to the nodes that have neither evidence nor
+notes, such as those left over from scope imports.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    emptyNodes, _ := gd.GetEmptyNodes(&project)
+ */
+func (gd *Godradis) GetEmptyNodes(project *Project) ([]*Node, error) {
+	nodes, err := gd.GetAllNodes(project)
+	if err != nil {
+		return []*Node{}, err
+	}
+	emptyNodes := []*Node{}
+	for i := range nodes {
+		if len(nodes[i].Evidence) == 0 && len(nodes[i].Notes) == 0 {
+			emptyNodes = append(emptyNodes, &nodes[i])
+		}
+	}
+	return emptyNodes, nil
+}
+
+/*
+FindDuplicateNodes takes a reference to a Project object and returns groups of nodes that share the same label, compared
+case-insensitively. Only labels used by more than one node are returned, and the nodes in each group are in the order
+returned by the server.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    groups, _ := gd.FindDuplicateNodes(&project)
+    for _, group := range groups {
+        fmt.Printf("%v: %v nodes\n", group[0].Label, len(group))
+    }
+ */
+func (gd *Godradis) FindDuplicateNodes(project *Project) ([][]*Node, error) {
+	nodes, err := gd.GetAllNodes(project)
+	if err != nil {
+		return [][]*Node{}, err
+	}
+	var labels []string
+	nodesByLabel := make(map[string][]*Node)
+	for i := range nodes {
+		label := strings.ToLower(nodes[i].Label)
+		if _, ok := nodesByLabel[label]; !ok {
+			labels = append(labels, label)
+		}
+		nodesByLabel[label] = append(nodesByLabel[label], &nodes[i])
+	}
+	groups := [][]*Node{}
+	for _, label := range labels {
+		if len(nodesByLabel[label]) > 1 {
+			groups = append(groups, nodesByLabel[label])
+		}
+	}
+	return groups, nil
+}
+
+/*
+MergeNodes copies the evidence and notes of every node in dupes onto primary, moves the duplicate's child nodes under
+primary and then deletes the duplicate. A duplicate is only deleted if everything on it was copied, so nothing is lost if
+a request fails partway through, but retrying the merge will copy again anything that was already copied from a duplicate
+that failed. The merge carries on past failures and a single error describing every duplicate that couldn't be fully
+merged is returned.
+
+Attachments can't be copied between nodes through the API, so a duplicate that has attachments is left untouched and
+reported as a failure; move its attachments by hand and merge it again. An error is returned before anything is changed if
+a duplicate is primary itself, belongs to a different project or is an ancestor of primary.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    groups, _ := gd.FindDuplicateNodes(&project)
+    for _, group := range groups {
+        err := gd.MergeNodes(group[0], group[1:])
+    }
+ */
+func (gd *Godradis) MergeNodes(primary *Node, dupes []*Node) error {
+	projectId, err := primary.projectId()
+	if err != nil {
+		return err
+	}
+	for _, dupe := range dupes {
+		if dupe.Id == primary.Id {
+			return errors.New(fmt.Sprintf("cannot merge node %v into itself", dupe.Id))
+		}
+		if dupe.Project == nil || dupe.Project.Id != projectId {
+			return errors.New(fmt.Sprintf("cannot merge node %v: it is not in project %v", dupe.Id, projectId))
+		}
+	}
+	nodes, err := gd.GetAllNodesShallow(primary.Project)
+	if err != nil {
+		return err
+	}
+	nodesById := make(map[int]*Node, len(nodes))
+	childrenById := make(map[int][]*Node)
+	for i := range nodes {
+		nodesById[nodes[i].Id] = &nodes[i]
+		childrenById[nodes[i].ParentId] = append(childrenById[nodes[i].ParentId], &nodes[i])
+	}
+	for _, dupe := range dupes {
+		// Walk up from primary so that a dupe's children are never moved under one of their own descendants
+		for ancestor, ok := nodesById[primary.Id]; ok && ancestor.ParentId != 0; ancestor, ok = nodesById[ancestor.ParentId] {
+			if ancestor.ParentId == dupe.Id {
+				return errors.New(fmt.Sprintf("cannot merge node %v: it is an ancestor of node %v", dupe.Id, primary.Id))
+			}
+		}
+	}
+	var failures []string
+	for _, dupe := range dupes {
+		err = gd.mergeNode(primary, dupe, childrenById[dupe.Id])
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("node %v: %v", dupe.Id, err))
+		}
+	}
+	if len(failures) > 0 {
+		return errors.New(fmt.Sprintf("could not merge %v of %v nodes: %s", len(failures), len(dupes), strings.Join(failures, "; ")))
+	}
+	return nil
+}
+
+func (gd *Godradis) mergeNode(primary, dupe *Node, children []*Node) error {
+	attachments, err := gd.GetAllAttachments(dupe)
+	if err != nil {
+		return err
+	}
+	if len(attachments) > 0 {
+		return errors.New(fmt.Sprintf("node has %v attachments that must be moved before it can be merged", len(attachments)))
+	}
+	evidences, err := gd.GetAllEvidence(dupe)
+	if err != nil {
+		return err
+	}
+	notes, err := gd.GetAllNotes(dupe)
+	if err != nil {
+		return err
+	}
+	for _, evidence := range evidences {
+		issue := Issue{Id: evidence.Issue.Id, Title: evidence.Issue.Title, Project: primary.Project}
+		_, err = gd.CreateEvidenceFromText(primary, &issue, evidence.Content)
+		if err != nil {
+			return err
+		}
+	}
+	for _, note := range notes {
+		_, err = gd.CreateNoteFromText(primary, note.Text, note.CategoryId)
+		if err != nil {
+			return err
+		}
+	}
+	for _, child := range children {
+		err = gd.MoveNode(child, primary.Id)
+		if err != nil {
+			return err
+		}
+	}
+	return gd.DeleteNode(dupe)
+}
+
+/*
+GetNodeById takes a reference to a Project object and int id and returns the node associated with that id.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    node, _ := gd.GetNodeById(&project, 7)
+ */
+func (gd *Godradis) GetNodeById(project *Project, id int) (Node, error) {
+	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v", id), project.Id, nil)
+	if err != nil {
+		return Node{}, err
+	}
+	defer resp.Body.Close()
+	var node Node
+	if resp.StatusCode != http.StatusOK {
+		return Node{}, errors.New("could not get node")
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Node{}, err
+	}
+
+	err = json.Unmarshal(body, &node)
+	if err != nil {
+		return Node{}, err
+	}
+	node.Project = project
+	node.setEvidenceNodeReferences()
+	node.setNoteNodeReferences()
+	return node, nil
+}
+
+/*
+GetNodeByLabel searches for and returns a Node object based on the label. GetNodeByLabel works by calling GetAllNodes
+first and then ranges over them comparing the label strings.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
+ */
+func (gd *Godradis) GetNodeByLabel(project *Project, label string) (Node, error) {
+	nodes, err := gd.GetAllNodes(project)
+	if err != nil {
+		return Node{}, err
+	}
+	for _, node := range nodes {
+		if gd.namesMatch(node.Label, label) {
+			return node, nil
+		}
 	}
+	return Node{}, errors.New(fmt.Sprintf("could not find node with label %s", label))
+}
+
+type nodeDetails struct {
+	Label string `json:"label,omitempty"`
+	TypeId NodeType `json:"type_id,omitempty"`
+	ParentId int `json:"parent_id,omitempty"`
+	Position int `json:"position,omitempty"`
+}
 
+func (nd *nodeDetails) parseArguments(label, typeId, parentId, position interface{}) error {
+	if label == nil {
+		nd.Label = ""
+	} else {
+		nd.Label = label.(string)
+	}
+	switch t := typeId.(type) {
+	case nil:
+		nd.TypeId = NodeTypeDefault
+	case NodeType:
+		nd.TypeId = t
+	case int:
+		nd.TypeId = NodeType(t)
+	default:
+		return errors.New(fmt.Sprintf("typeId must be a NodeType or an int, not %T", typeId))
+	}
+	if parentId == nil {
+		nd.ParentId = 0
+	} else {
+		nd.ParentId = parentId.(int)
+	}
+	if position == nil {
+		nd.Position = 0
+	} else {
+		nd.Position = position.(int)
+	}
+	return nil
+}
+
+/*
+CreateNode takes a reference to a Project object and several mandatory properties and creates a new Node on the server
+and returns it. label is a string representing the name of the node. typeId is a NodeType and can be NodeTypeDefault (0)
+or NodeTypeHost (1); any other value is rejected. parentId is an int indicating the ID of the parent node if there is one, otherwise it will be created
+as a top-level node. position is an int that determines where to insert the node within the existing node structure.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    node, _ := gd.CreateNode(&project, "127.0.0.1", godradis.NodeTypeHost, 14, 3)
+ */
+func (gd *Godradis) CreateNode(project *Project, label string, typeId NodeType, parentId int, position int) (Node, error) {
+	// BUG(njfox): The parentId argument to CreateNode may not be correctly serialized in the API request
+
+	// Required so that json.Marshal() sends the fields wrapped in a node{} json object
+	type reqModel struct {
+		Node nodeDetails `json:"node"`
+	}
+
+	err := validateNodeType(typeId)
+	if err != nil {
+		return Node{}, err
+	}
 	nd := nodeDetails{label, typeId, parentId, position}
 	jsonBody, err := json.Marshal(&reqModel{nd})
 	if err != nil {
@@ -758,6 +1994,9 @@ func (gd *Godradis) CreateNode(project *Project, label string, typeId int, paren
 	}
 	defer resp.Body.Close()
 	var newNode Node
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return Node{}, parseValidationError(resp)
+	}
 	if resp.StatusCode != http.StatusCreated {
 		return Node{}, errors.New("could not create node")
 	}
@@ -770,13 +2009,323 @@ func (gd *Godradis) CreateNode(project *Project, label string, typeId int, paren
 	if err != nil {
 		return Node{}, err
 	}
-	newNode.Project = project
-	newNode.setEvidenceNodeReferences()
-	return newNode, nil
+	newNode.Project = project
+	newNode.setEvidenceNodeReferences()
+	return newNode, nil
+}
+
+/*
+CreateNodeTree takes a reference to a Project object and a slice of NodeSpec trees, and creates the nodes top-down so that
+each child is created under the ID its parent was given by the server. Siblings are positioned in slice order. If a node
+can't be created its subtree is skipped, the rest of the tree is still created, and an error listing the failures is
+returned along with the nodes that were created. The created nodes are returned parents first, with ParentId set.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    nodes, err := gd.CreateNodeTree(&project, []godradis.NodeSpec{
+        {Label: "10.0.0.1", TypeId: godradis.NodeTypeHost, Children: []godradis.NodeSpec{
+            {Label: "22/tcp"},
+            {Label: "443/tcp"},
+        }},
+    })
+ */
+func (gd *Godradis) CreateNodeTree(project *Project, roots []NodeSpec) ([]*Node, error) {
+	created := []*Node{}
+	var failures []string
+	gd.createNodeTree(project, roots, 0, &created, &failures)
+	if len(failures) > 0 {
+		return created, errors.New(fmt.Sprintf("could not create %v nodes: %s", len(failures), strings.Join(failures, "; ")))
+	}
+	return created, nil
+}
+
+func (gd *Godradis) createNodeTree(project *Project, specs []NodeSpec, parentId int, created *[]*Node, failures *[]string) {
+	for i, spec := range specs {
+		node, err := gd.CreateNode(project, spec.Label, spec.TypeId, parentId, i)
+		if err != nil {
+			*failures = append(*failures, fmt.Sprintf("node %s (and its children): %v", spec.Label, err))
+			continue
+		}
+		*created = append(*created, &node)
+		gd.createNodeTree(project, spec.Children, node.Id, created, failures)
+	}
+}
+
+/*
+GetOrCreateNode takes a reference to a Project object, a label, and a typeId and returns the existing node with a
+matching label (compared case-insensitively unless Config.CaseSensitiveNames is set) if there is one. Otherwise a new
+top-level node is created with CreateNode. The node is returned by pointer, since Node holds a mutex and must not be
+copied. The bool return value is true only if a new node was created, which makes GetOrCreateNode safe to call repeatedly
+from import scripts without producing duplicate nodes.
+
+Note that the lookup and creation are two separate API requests, so two callers racing on the same label may still both
+create a node. Callers that run imports concurrently should serialize calls for the same project.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    node, created, _ := gd.GetOrCreateNode(&project, "127.0.0.1", godradis.NodeTypeHost)
+    if created {
+        fmt.Printf("created node %v", node.Id)
+    }
+ */
+func (gd *Godradis) GetOrCreateNode(project *Project, label string, typeId NodeType) (*Node, bool, error) {
+	nodes, err := gd.GetAllNodes(project)
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range nodes {
+		if gd.namesMatch(nodes[i].Label, label) {
+			return &nodes[i], false, nil
+		}
+	}
+	node, err := gd.CreateNode(project, label, typeId, 0, 0)
+	if err != nil {
+		return nil, false, err
+	}
+	return &node, true, nil
+}
+
+/*
+UpdateNode takes a reference to an existing Node object and updates any non-nil properties passed to it as arguments.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
+    _ := gd.UpdateNode(&node, "localhost", nil, nil, nil)
+ */
+func (gd *Godradis) UpdateNode(n *Node, label, typeId, parentId, position interface{}) error {
+	projectId, err := n.projectId()
+	if err != nil {
+		return err
+	}
+	// Required so that json.Marshal() sends the fields wrapped in a node{} json object
+	type reqModel struct {
+		NodeDetails nodeDetails `json:"node"`
+	}
+	nd := nodeDetails{}
+	err = nd.parseArguments(label, typeId, parentId, position)
+	if err != nil {
+		return err
+	}
+	if typeId != nil {
+		err := validateNodeType(nd.TypeId)
+		if err != nil {
+			return err
+		}
+	}
+	jsonBody, err := json.Marshal(&reqModel{nd})
+	if err != nil {
+		return err
+	}
+	resp, err := gd.sendRequestWithProjectId("PUT", fmt.Sprintf("nodes/%v", n.Id), projectId, jsonBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return parseValidationError(resp)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("could not update node")
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(body, &n)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+/*
+MoveNode takes a reference to an existing Node object and moves it under the node with id newParentId. A newParentId of 0
+moves the node to the top level of the project. UpdateNode can't be used for this because it omits a zero parentId from
+the request, so the node would stay where it is.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
+    _ := gd.MoveNode(&node, 0)
+ */
+func (gd *Godradis) MoveNode(n *Node, newParentId int) error {
+	projectId, err := n.projectId()
+	if err != nil {
+		return err
+	}
+	// Required so that json.Marshal() sends the fields wrapped in a node{} json object. ParentId has no omitempty so that
+	// a top-level move is sent as a null parent_id.
+	type moveDetails struct {
+		ParentId *int `json:"parent_id"`
+	}
+	type reqModel struct {
+		MoveDetails moveDetails `json:"node"`
+	}
+	md := moveDetails{}
+	if newParentId != 0 {
+		md.ParentId = &newParentId
+	}
+	jsonBody, err := json.Marshal(&reqModel{md})
+	if err != nil {
+		return err
+	}
+	resp, err := gd.sendRequestWithProjectId("PUT", fmt.Sprintf("nodes/%v", n.Id), projectId, jsonBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return parseValidationError(resp)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("could not move node")
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(body, &n)
+	if err != nil {
+		return err
+	}
+	// A null parent_id in the response leaves the old value in place
+	n.ParentId = newParentId
+	return nil
+}
+
+/*
+ReparentNodes moves every node in nodes under the node with id newParentId (or to the top level if newParentId is 0) using
+MoveNode. All nodes are attempted even if some fail, and a single error describing every failed node is returned. The
+ParentId of each successfully moved node is updated in place.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    parent, _ := gd.GetNodeByLabel(&project, "Internal Hosts")
+    err := gd.ReparentNodes([]*godradis.Node{&node1, &node2}, parent.Id)
+ */
+func (gd *Godradis) ReparentNodes(nodes []*Node, newParentId int) error {
+	_, err := gd.ReparentNodesContext(context.Background(), nodes, newParentId)
+	return err
+}
+
+/*
+ReparentNodesContext behaves like ReparentNodes but checks ctx before each node and also returns the number of nodes that
+were moved. If ctx is cancelled the remaining nodes are left where they are and ctx.Err() is returned, wrapped with a
+description of any nodes that had already failed to move; errors.Cause still returns ctx.Err().
+ */
+func (gd *Godradis) ReparentNodesContext(ctx context.Context, nodes []*Node, newParentId int) (int, error) {
+	moved := 0
+	var failures []string
+	summary := func() string {
+		return fmt.Sprintf("could not move %v of %v nodes: %s", len(failures), len(nodes), strings.Join(failures, "; "))
+	}
+	for _, node := range nodes {
+		if ctx.Err() != nil {
+			if len(failures) > 0 {
+				return moved, errors.Wrap(ctx.Err(), summary())
+			}
+			return moved, ctx.Err()
+		}
+		err := gd.MoveNode(node, newParentId)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("node %v: %v", node.Id, err))
+			continue
+		}
+		moved++
+	}
+	if len(failures) > 0 {
+		return moved, errors.New(summary())
+	}
+	return moved, nil
+}
+
+/*
+CloneNode takes a reference to an existing Node object and creates a copy of it labelled newLabel under the node with id
+newParentId (or at the top level if newParentId is 0). The source node's notes and all of its child nodes, recursively,
+are copied as well; the children keep their original labels. If withEvidence is true the evidence on every copied node is
+recreated and attached to the same issues as the original, otherwise no evidence is copied. A reference to the new top-level
+node is returned.
+
+Cloning stops at the first error, so a failed call may leave a partial copy on the server.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
+    clone, _ := gd.CloneNode(&node, "127.0.0.2", node.ParentId, true)
+ */
+func (gd *Godradis) CloneNode(source *Node, newLabel string, newParentId int, withEvidence bool) (*Node, error) {
+	_, err := source.projectId()
+	if err != nil {
+		return nil, err
+	}
+	// Work from a fresh copy of the project's nodes so the source's evidence, notes and children are current
+	nodes, err := gd.GetAllNodes(source.Project)
+	if err != nil {
+		return nil, err
+	}
+	nodesById := make(map[int]*Node, len(nodes))
+	childrenById := make(map[int][]*Node)
+	for i := range nodes {
+		nodesById[nodes[i].Id] = &nodes[i]
+		childrenById[nodes[i].ParentId] = append(childrenById[nodes[i].ParentId], &nodes[i])
+	}
+	current, ok := nodesById[source.Id]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("could not find node %v on the server", source.Id))
+	}
+	return gd.cloneNode(current, newLabel, newParentId, withEvidence, childrenById)
+}
+
+func (gd *Godradis) cloneNode(source *Node, label string, parentId int, withEvidence bool, childrenById map[int][]*Node) (*Node, error) {
+	newNode, err := gd.CreateNode(source.Project, label, source.TypeId, parentId, source.Position)
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range source.Notes {
+		_, err = gd.CreateNoteFromText(&newNode, note.Text, note.CategoryId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if withEvidence {
+		for _, evidence := range source.Evidence {
+			issue := Issue{Id: evidence.Issue.Id, Title: evidence.Issue.Title, Project: source.Project}
+			_, err = gd.CreateEvidenceFromText(&newNode, &issue, evidence.Content)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, child := range childrenById[source.Id] {
+		_, err = gd.cloneNode(child, child.Label, newNode.Id, withEvidence, childrenById)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &newNode, nil
 }
 
 /*
-UpdateNode takes a reference to an existing Node object and updates any non-nil properties passed to it as arguments.
+DeleteNode takes a reference to an existing Node object and deletes it on the server.
+The API has no endpoint for listing or restoring deleted items, so a deleted node can't be recovered through godradis.
 
     gd := godradis.Godradis{}
 
@@ -784,59 +2333,75 @@ UpdateNode takes a reference to an existing Node object and updates any non-nil
 
     project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
     node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
-    _ := gd.UpdateNode(&node, "localhost", nil, nil, nil)
+    _ := gd.DeleteNode(&node)
  */
-func (gd *Godradis) UpdateNode(n *Node, label, typeId, parentId, position interface{}) error {
-	// Required so that json.Marshal() sends the fields wrapped in a node{} json object
-	type reqModel struct {
-		NodeDetails nodeDetails `json:"node"`
-	}
-	nd := nodeDetails{}
-	nd.parseArguments(label, typeId, parentId, position)
-	jsonBody, err := json.Marshal(&reqModel{nd})
-	if err != nil {
-		return err
-	}
-	resp, err := gd.sendRequestWithProjectId("PUT", fmt.Sprintf("nodes/%v", n.Id), n.Project.Id, jsonBody)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return errors.New("could not update node")
-	}
-	body, err := ioutil.ReadAll(resp.Body)
+func (gd *Godradis) DeleteNode(n *Node) error {
+	projectId, err := n.projectId()
 	if err != nil {
 		return err
 	}
-	err = json.Unmarshal(body, &n)
+	resp, err := gd.sendRequestWithProjectId("DELETE", fmt.Sprintf("nodes/%v", n.Id), projectId, nil)
 	if err != nil {
 		return err
 	}
-	return nil
+	return gd.checkDelete(resp, "could not delete node")
 }
 
 /*
-DeleteNode takes a reference to an existing Node object and deletes it on the server.
+DeleteNodeRecursive deletes a node along with all of its descendants, which DeleteNode leaves behind on the server. The
+project's node tree is fetched first and the descendants are deleted depth-first before the node itself. Deletion carries
+on past failures, and a single error describing every node that couldn't be deleted is returned. Use with care: this can
+remove a large part of a project in one call. An error is returned without deleting anything if n has no id or isn't one
+of the project's nodes.
 
     gd := godradis.Godradis{}
 
     [...]
 
     project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
-    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
-    _ := gd.DeleteNode(&node)
+    node, _ := gd.GetNodeByLabel(&project, "Out of scope")
+    err := gd.DeleteNodeRecursive(&node)
  */
-func (gd *Godradis) DeleteNode(n *Node) error {
-	resp, err := gd.sendRequestWithProjectId("DELETE", fmt.Sprintf("nodes/%v", n.Id), n.Project.Id, nil)
+func (gd *Godradis) DeleteNodeRecursive(n *Node) error {
+	_, err := n.projectId()
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode == http.StatusOK {
-		return nil
-	} else {
-		return errors.New("could not delete node")
+	if n.Id == 0 {
+		return errors.New("cannot recursively delete a node with no id")
+	}
+	nodes, err := gd.GetAllNodesShallow(n.Project)
+	if err != nil {
+		return err
+	}
+	found := false
+	childrenById := make(map[int][]*Node)
+	for i := range nodes {
+		if nodes[i].Id == n.Id {
+			found = true
+		}
+		childrenById[nodes[i].ParentId] = append(childrenById[nodes[i].ParentId], &nodes[i])
 	}
+	if !found {
+		return errors.New(fmt.Sprintf("could not find node %v on the server", n.Id))
+	}
+
+	var failures []string
+	var deleteTree func(node *Node)
+	deleteTree = func(node *Node) {
+		for _, child := range childrenById[node.Id] {
+			deleteTree(child)
+		}
+		err := gd.DeleteNode(node)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("node %v: %v", node.Id, err))
+		}
+	}
+	deleteTree(n)
+	if len(failures) > 0 {
+		return errors.New(fmt.Sprintf("could not delete %v nodes: %s", len(failures), strings.Join(failures, "; ")))
+	}
+	return nil
 }
 
 // Issues endpoint
@@ -876,6 +2441,88 @@ func (gd *Godradis) GetAllIssues(project *Project) ([]Issue, error) {
 	return issues, nil
 }
 
+/*
+GetIssuesPage takes a reference to a Project object and a page number (starting at 1) and returns a single page of the
+project's issues along with the pagination metadata reported by the server.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    issuesPage, _ := gd.GetIssuesPage(&project, 2)
+ */
+func (gd *Godradis) GetIssuesPage(project *Project, page int) (IssuesPage, error) {
+	body, header, err := gd.getPage("issues", project.Id, page)
+	if err != nil {
+		return IssuesPage{}, err
+	}
+	var issues []Issue
+	err = json.Unmarshal(body, &issues)
+	if err != nil {
+		return IssuesPage{}, err
+	}
+	for i := 0; i < len(issues); i++ {
+		issues[i].Project = project
+	}
+	return IssuesPage{parseListResult(header, page), issues}, nil
+}
+
+/*
+IssuesIterator takes a reference to a Project object and a callback and calls the callback once for every issue in the
+project, requesting the issues from the server one page at a time instead of loading the full list into memory. As with
+NodesIterator, the callback is passed a pointer to each issue. Returning ErrStopIteration from the callback stops the
+iteration early without an error; any other error stops the iteration and is returned.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    err := gd.IssuesIterator(&project, func(issue *godradis.Issue) error {
+        if issue.Title == "Cross-Site Scripting" {
+            return godradis.ErrStopIteration
+        }
+        return nil
+    })
+ */
+func (gd *Godradis) IssuesIterator(project *Project, fn func(*Issue) error) error {
+	return gd.eachPage("issues", project.Id, func(body []byte, _ http.Header, isNew func(int) bool) error {
+		var issues []Issue
+		err := json.Unmarshal(body, &issues)
+		if err != nil {
+			return err
+		}
+		for i := range issues {
+			if !isNew(issues[i].Id) {
+				continue
+			}
+			issues[i].Project = project
+			err = fn(&issues[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+/*
+CountIssues takes a reference to a Project object and returns the number of issues in the project. If the server reports
+the total in an X-Total-Count or Total header only the first page is requested; otherwise the issues are fetched page by
+page and only their IDs are decoded.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    count, _ := gd.CountIssues(&project)
+ */
+func (gd *Godradis) CountIssues(project *Project) (int, error) {
+	return gd.countResource("issues", project.Id)
+}
+
 /*
 GetIssueById takes a reference to a Project object and int id and returns the Issue associated with that id.
 
@@ -927,11 +2574,334 @@ func (gd *Godradis) GetIssueByTitle(project *Project, title string) (Issue, erro
 		return Issue{}, err
 	}
 	for _, issue := range issues {
-		if strings.ToLower(issue.Title) == strings.ToLower(title) {
+		if gd.namesMatch(issue.Title, title) {
 			return issue, nil
 		}
 	}
-	return Issue{}, errors.New(fmt.Sprintf("could not find issue with title %s", title))
+	return Issue{}, errors.New(fmt.Sprintf("could not find issue with title %s", title))
+}
+
+/*
+GetIssuesByTagFromServer takes a reference to a Project object and a tag and returns the project's issues that have the
+tag. The tag is sent to the server as a query parameter so that servers supporting it only return the matching issues,
+and the results are also filtered locally with Issue.HasTag, so the result is the same on servers that ignore the
+parameter and return every issue.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    issues, _ := gd.GetIssuesByTagFromServer(&project, "!d62728_critical")
+ */
+func (gd *Godradis) GetIssuesByTagFromServer(project *Project, tag string) ([]Issue, error) {
+	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("issues?tag=%s", url.QueryEscape(tag)), project.Id, nil)
+	if err != nil {
+		return []Issue{}, err
+	}
+	defer resp.Body.Close()
+	var issues []Issue
+	if resp.StatusCode != http.StatusOK {
+		return []Issue{}, errors.New("could not get issue list")
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return []Issue{}, err
+	}
+
+	err = json.Unmarshal(body, &issues)
+	if err != nil {
+		return []Issue{}, err
+	}
+	tagged := []Issue{}
+	for _, issue := range issues {
+		if issue.HasTag(tag) {
+			issue.Project = project
+			tagged = append(tagged, issue)
+		}
+	}
+	return tagged, nil
+}
+
+/*
+IssuesByNode takes a reference to a Project object and returns the project's issues grouped by the nodes they have
+evidence on, keyed by node ID. Each issue appears at most once per node no matter how many evidence instances link them,
+and nodes without evidence are left out of the map.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    issuesByNode, _ := gd.IssuesByNode(&project)
+    for nodeId, issues := range issuesByNode {
+        fmt.Printf("%v: %v issues\n", nodeId, len(issues))
+    }
+ */
+func (gd *Godradis) IssuesByNode(project *Project) (map[int][]Issue, error) {
+	nodes, err := gd.GetAllNodes(project)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := gd.GetAllIssues(project)
+	if err != nil {
+		return nil, err
+	}
+	issuesById := make(map[int]Issue, len(issues))
+	for _, issue := range issues {
+		issuesById[issue.Id] = issue
+	}
+
+	issuesByNode := make(map[int][]Issue)
+	for i := range nodes {
+		seen := make(map[int]bool)
+		for _, evidence := range nodes[i].Evidence {
+			issue, ok := issuesById[evidence.Issue.Id]
+			if !ok || seen[issue.Id] {
+				continue
+			}
+			seen[issue.Id] = true
+			issuesByNode[nodes[i].Id] = append(issuesByNode[nodes[i].Id], issue)
+		}
+	}
+	return issuesByNode, nil
+}
+
+/*
+EvidenceCountByIssue takes a reference to a Project object and returns the number of evidence instances attached to each
+issue across all of the project's nodes, keyed by issue ID. The counts are tallied from a single GetAllNodes call rather
+than one request per issue. Issues without evidence are not included in the map.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    counts, _ := gd.EvidenceCountByIssue(&project)
+    issue, _ := gd.GetIssueByTitle(&project, "Cross-Site Scripting")
+    fmt.Printf("%v instances", counts[issue.Id])
+ */
+func (gd *Godradis) EvidenceCountByIssue(project *Project) (map[int]int, error) {
+	nodes, err := gd.GetAllNodes(project, WithNotes(false))
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int]int)
+	for i := range nodes {
+		for _, evidence := range nodes[i].Evidence {
+			counts[evidence.Issue.Id]++
+		}
+	}
+	return counts, nil
+}
+
+/*
+GetIssuesUpdatedSince takes a reference to a Project object and returns every issue whose UpdatedAt timestamp is strictly
+after t, so passing the time of the last sync doesn't return issues that were already seen at that time. The Issues
+endpoint has no server-side filter, so all issues are fetched with GetAllIssues and filtered locally.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    issues, _ := gd.GetIssuesUpdatedSince(&project, lastSync)
+ */
+func (gd *Godradis) GetIssuesUpdatedSince(project *Project, t time.Time) ([]Issue, error) {
+	issues, err := gd.GetAllIssues(project)
+	if err != nil {
+		return []Issue{}, err
+	}
+	updated := []Issue{}
+	for _, issue := range issues {
+		updatedAt, err := issue.UpdatedTime()
+		if err != nil {
+			return []Issue{}, err
+		}
+		if updatedAt.After(t) {
+			updated = append(updated, issue)
+		}
+	}
+	return updated, nil
+}
+
+/*
+GetIssuesWithoutEvidence takes a reference to a Project object and returns the project's issues that no evidence on any
+node references, using the counts from EvidenceCountByIssue.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    issues, _ := gd.GetIssuesWithoutEvidence(&project)
+    for _, issue := range issues {
+        fmt.Printf("%s has no evidence\n", issue.Title)
+    }
+ */
+func (gd *Godradis) GetIssuesWithoutEvidence(project *Project) ([]Issue, error) {
+	issues, err := gd.GetAllIssues(project)
+	if err != nil {
+		return []Issue{}, err
+	}
+	counts, err := gd.EvidenceCountByIssue(project)
+	if err != nil {
+		return []Issue{}, err
+	}
+	unreferenced := []Issue{}
+	for _, issue := range issues {
+		if counts[issue.Id] == 0 {
+			unreferenced = append(unreferenced, issue)
+		}
+	}
+	return unreferenced, nil
+}
+
+/*
+DistinctIssueFieldKeys takes a reference to a Project object and returns the union of the field names used by all of the
+project's issues, in the order they are first seen. This is useful for spotting inconsistently named fields.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    keys, _ := gd.DistinctIssueFieldKeys(&project)
+    fmt.Println(strings.Join(keys, ", "))
+ */
+func (gd *Godradis) DistinctIssueFieldKeys(project *Project) ([]string, error) {
+	issues, err := gd.GetAllIssues(project)
+	if err != nil {
+		return []string{}, err
+	}
+	return distinctFieldKeys(issues), nil
+}
+
+// distinctFieldKeys returns the union of the issues' field names in the order they are first seen
+func distinctFieldKeys(issues []Issue) []string {
+	seen := make(map[string]bool)
+	keys := []string{}
+	for _, issue := range issues {
+		for _, k := range issue.Fields.Keys() {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+/*
+DiffProjectsIssues compares the issues of two projects, matched by title compared case-insensitively. Issues only in b
+are Added, issues only in a are Removed, and issues in both whose fields differ are listed in Changed with the names of
+the differing fields from FieldsDiff, ignoring the order of the fields. This is meant for comparing a retest (b) against
+the original engagement (a).
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    original, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    retest, _ := gd.GetProjectByName("Foobar External Network Penetration Test - Retest")
+    diff, _ := gd.DiffProjectsIssues(&original, &retest)
+    for _, change := range diff.Changed {
+        fmt.Printf("%s: %s\n", change.After.Title, strings.Join(change.Fields, ", "))
+    }
+ */
+func (gd *Godradis) DiffProjectsIssues(a, b *Project) (IssueSetDiff, error) {
+	aIssues, err := gd.GetAllIssues(a)
+	if err != nil {
+		return IssueSetDiff{}, err
+	}
+	bIssues, err := gd.GetAllIssues(b)
+	if err != nil {
+		return IssueSetDiff{}, err
+	}
+	aByTitle := make(map[string]Issue, len(aIssues))
+	for _, issue := range aIssues {
+		aByTitle[strings.ToLower(issue.Title)] = issue
+	}
+	bTitles := make(map[string]bool, len(bIssues))
+	diff := IssueSetDiff{Added: []Issue{}, Removed: []Issue{}, Changed: []IssueChange{}}
+	for _, issue := range bIssues {
+		title := strings.ToLower(issue.Title)
+		bTitles[title] = true
+		before, ok := aByTitle[title]
+		if !ok {
+			diff.Added = append(diff.Added, issue)
+			continue
+		}
+		fields := FieldsDiff(&before.Fields, &issue.Fields, false)
+		if len(fields) > 0 {
+			diff.Changed = append(diff.Changed, IssueChange{Before: before, After: issue, Fields: fields})
+		}
+	}
+	for _, issue := range aIssues {
+		if !bTitles[strings.ToLower(issue.Title)] {
+			diff.Removed = append(diff.Removed, issue)
+		}
+	}
+	return diff, nil
+}
+
+/*
+SearchIssuesAcrossProjects returns the issues in every project on the server whose title contains title, compared
+case-insensitively unless Config.CaseSensitiveNames is set. Each returned Issue has its Project set. Projects are
+queried a few at a time and the results are ordered by project in the order returned by GetAllProjects. If some projects
+can't be queried, the matches from the others are returned along with an error describing the failures.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    issues, _ := gd.SearchIssuesAcrossProjects("Cross-Site Scripting")
+    for _, issue := range issues {
+        fmt.Printf("%v: %v\n", issue.Project.Name, issue.Title)
+    }
+ */
+func (gd *Godradis) SearchIssuesAcrossProjects(title string) ([]Issue, error) {
+	projects, err := gd.GetAllProjects()
+	if err != nil {
+		return []Issue{}, err
+	}
+
+	matches := make([][]Issue, len(projects))
+	errs := make([]error, len(projects))
+	sem := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i := range projects {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			issues, err := gd.GetAllIssues(&projects[i])
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for _, issue := range issues {
+				if gd.nameContains(issue.Title, title) {
+					matches[i] = append(matches[i], issue)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	results := []Issue{}
+	var failures []string
+	for i := range projects {
+		results = append(results, matches[i]...)
+		if errs[i] != nil {
+			failures = append(failures, fmt.Sprintf("project %v: %v", projects[i].Id, errs[i]))
+		}
+	}
+	if len(failures) > 0 {
+		return results, errors.New(fmt.Sprintf("could not search %v of %v projects: %s", len(failures), len(projects), strings.Join(failures, "; ")))
+	}
+	return results, nil
 }
 
 /*
@@ -950,7 +2920,10 @@ new Issue on the server, and returns it.
     issue, _ := gd.CreateIssue(&project, fields)
  */
 func (gd *Godradis) CreateIssue(project *Project, fields *orderedmap.OrderedMap) (Issue, error) {
-	text := parseOrderedMapFields(fields)
+	text, err := gd.parseFields(fields)
+	if err != nil {
+		return Issue{}, err
+	}
 	issue, err := gd.CreateIssueFromText(project, text)
 	if err != nil {
 		return Issue{}, err
@@ -959,6 +2932,39 @@ func (gd *Godradis) CreateIssue(project *Project, fields *orderedmap.OrderedMap)
 	return issue, nil
 }
 
+/*
+GetOrCreateIssue takes a reference to a Project object, a title, and an OrderedMap containing the fields in the Issue
+body. If an issue with a matching title (compared case-insensitively unless Config.CaseSensitiveNames is set) already
+exists it is returned unchanged, otherwise a new Issue is created from fields with CreateIssue. The bool return value is
+true only if a new issue was created.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    fields := orderedmap.New()
+    fields.Set("Title", "Insecure Password Storage")
+    fields.Set("Severity", "High")
+    issue, created, _ := gd.GetOrCreateIssue(&project, "Insecure Password Storage", fields)
+ */
+func (gd *Godradis) GetOrCreateIssue(project *Project, title string, fields *orderedmap.OrderedMap) (Issue, bool, error) {
+	issues, err := gd.GetAllIssues(project)
+	if err != nil {
+		return Issue{}, false, err
+	}
+	for _, issue := range issues {
+		if gd.namesMatch(issue.Title, title) {
+			return issue, false, nil
+		}
+	}
+	issue, err := gd.CreateIssue(project, fields)
+	if err != nil {
+		return Issue{}, false, err
+	}
+	return issue, true, nil
+}
+
 /*
 CreateIssueFromText provides an alternate method for creating issues directly from a text string as opposed to the
 OrderedMap approach used by CreateIssue. CreateIssueFromText takes a reference to a Project object and a string containing
@@ -990,6 +2996,9 @@ func (gd *Godradis) CreateIssueFromText(project *Project, text string) (Issue, e
 	}
 	defer resp.Body.Close()
 	var newIssue Issue
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return Issue{}, parseValidationError(resp)
+	}
 	if resp.StatusCode != http.StatusCreated {
 		return Issue{}, errors.New("could not create issue")
 	}
@@ -1023,14 +3032,50 @@ that are being modified.
     _ := gd.UpdateIssue(&issue, fields)
  */
 func (gd *Godradis) UpdateIssue(issue *Issue, fields *orderedmap.OrderedMap) error {
-	text := parseOrderedMapFields(fields)
-	err := gd.UpdateIssueFromText(issue, text)
+	text, err := gd.parseFields(fields)
+	if err != nil {
+		return err
+	}
+	err = gd.UpdateIssueFromText(issue, text)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+/*
+UpdateIssueIfUnchanged behaves like UpdateIssue but first fetches the issue from the server and compares its UpdatedAt
+timestamp with the local Issue object's. If they differ, someone else has modified the issue since it was fetched and
+ErrConflict is returned without updating anything. Note that the check and the update are separate requests, so a write
+landing between them is not detected.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    issue, _ := gd.GetIssueByTitle(&project, "Insecure Password Storage")
+    fields := issue.Fields
+    fields.Set("Severity", "Medium")
+    err := gd.UpdateIssueIfUnchanged(&issue, &fields)
+    if err == godradis.ErrConflict {
+        fmt.Println("issue was changed by someone else")
+    }
+ */
+func (gd *Godradis) UpdateIssueIfUnchanged(issue *Issue, fields *orderedmap.OrderedMap) error {
+	_, err := issue.projectId()
+	if err != nil {
+		return err
+	}
+	current, err := gd.GetIssueById(issue.Project, issue.Id)
+	if err != nil {
+		return err
+	}
+	if !sameTimestamp(current.UpdatedAt, issue.UpdatedAt) {
+		return ErrConflict
+	}
+	return gd.UpdateIssue(issue, fields)
+}
+
 /*
 UpdateIssueFromText provides an alternate method for updating issues directly from a text string as opposed to the
 OrderedMap approach used by UpdateIssue. UpdateIssueFromText takes a reference to an existing Issue object and a string
@@ -1046,6 +3091,10 @@ works, all fields in the body must be passed in the string, not just the fields
     _ := gd.UpdateIssueFromText(&issue, "#[Title]#\r\nInsecure Password Storage\r\n\r\n#[Severity]#\r\Medium")
  */
 func (gd *Godradis) UpdateIssueFromText(issue *Issue, text string) error {
+	projectId, err := issue.projectId()
+	if err != nil {
+		return err
+	}
 	// Required so that json.Marshal() sends the fields wrapped in a issue{} json object
 	type issueDetails struct {
 		Text string `json:"text"`
@@ -1058,11 +3107,14 @@ func (gd *Godradis) UpdateIssueFromText(issue *Issue, text string) error {
 	if err != nil {
 		return err
 	}
-	resp, err := gd.sendRequestWithProjectId("PUT", fmt.Sprintf("issues/%v", issue.Id), issue.Project.Id, jsonBody)
+	resp, err := gd.sendRequestWithProjectId("PUT", fmt.Sprintf("issues/%v", issue.Id), projectId, jsonBody)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return parseValidationError(resp)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return errors.New("could not update issue")
 	}
@@ -1078,8 +3130,165 @@ func (gd *Godradis) UpdateIssueFromText(issue *Issue, text string) error {
 	return nil
 }
 
+/*
+TagIssues adds tag to the Tags field of every issue in issues and updates them on the server. Issues that already have the
+tag are left alone, so re-tagging is a no-op. All issues are attempted even if some fail, and a single error describing
+every failed issue is returned.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    xss, _ := gd.GetIssueByTitle(&project, "Cross-Site Scripting")
+    sqli, _ := gd.GetIssueByTitle(&project, "SQL Injection")
+    err := gd.TagIssues([]*godradis.Issue{&xss, &sqli}, "!d62728_critical")
+ */
+func (gd *Godradis) TagIssues(issues []*Issue, tag string) error {
+	var failures []string
+	for _, issue := range issues {
+		if issue.HasTag(tag) {
+			continue
+		}
+		fields := orderedmap.New()
+		for _, k := range issue.Fields.Keys() {
+			v, _ := issue.Fields.Get(k)
+			fields.Set(k, v)
+		}
+		fields.Set("Tags", strings.Join(append(issue.Tags(), tag), ", "))
+		err := gd.UpdateIssue(issue, fields)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("issue %v: %v", issue.Id, err))
+		}
+	}
+	if len(failures) > 0 {
+		return errors.New(fmt.Sprintf("could not tag %v of %v issues: %s", len(failures), len(issues), strings.Join(failures, "; ")))
+	}
+	return nil
+}
+
+/*
+NormalizeSeverities takes a reference to a Project object and a mapping from severity labels to canonical labels, and
+rewrites the Severity field of every issue in the project whose label is in the mapping. Labels are looked up exactly
+first and then case-insensitively, so {"high": "High"} also normalizes "HIGH". Issues without a Severity field, with a
+label that isn't in the mapping, or that already have the canonical label are left alone. An error is returned before
+any issue is changed if two labels differ only by case but map to different canonical labels. The number of issues that
+were updated is returned, along with a single error describing any issues that couldn't be updated.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    changed, _ := gd.NormalizeSeverities(&project, map[string]string{"hi": "High", "high": "High", "med": "Medium"})
+ */
+func (gd *Godradis) NormalizeSeverities(project *Project, mapping map[string]string) (int, error) {
+	// Labels differing only by case would make the case-insensitive fallback depend on map iteration order
+	labels := make([]string, 0, len(mapping))
+	for label := range mapping {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for i := range labels {
+		for _, other := range labels[i+1:] {
+			if strings.EqualFold(labels[i], other) && mapping[labels[i]] != mapping[other] {
+				return 0, errors.New(fmt.Sprintf("severity labels %q and %q differ only by case but map to %q and %q",
+					labels[i], other, mapping[labels[i]], mapping[other]))
+			}
+		}
+	}
+	issues, err := gd.GetAllIssues(project)
+	if err != nil {
+		return 0, err
+	}
+	changed := 0
+	var failures []string
+	for i := range issues {
+		value, ok := issues[i].Fields.Get("Severity")
+		if !ok {
+			continue
+		}
+		severity := strings.TrimSpace(fmt.Sprintf("%v", value))
+		canonical, ok := mapping[severity]
+		if !ok {
+			for _, label := range labels {
+				if strings.EqualFold(label, severity) {
+					canonical, ok = mapping[label], true
+					break
+				}
+			}
+		}
+		if !ok || canonical == severity {
+			continue
+		}
+		fields := issues[i].CopyFields()
+		fields.Set("Severity", canonical)
+		err = gd.UpdateIssue(&issues[i], &fields)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("issue %v: %v", issues[i].Id, err))
+			continue
+		}
+		changed++
+	}
+	if len(failures) > 0 {
+		return changed, errors.New(fmt.Sprintf("could not normalize severity of %v issues: %s", len(failures), strings.Join(failures, "; ")))
+	}
+	return changed, nil
+}
+
+/*
+SetIssueSeverities takes a reference to a Project object and a mapping from issue titles to severities, and sets the
+Severity field of each issue whose title is in the mapping, compared case-insensitively. The other fields of the issue
+are kept as they are. The titles that didn't match any issue are returned in sorted order, along with a single error
+describing any issues that couldn't be updated.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    unmatched, err := gd.SetIssueSeverities(&project, map[string]string{"Cross-Site Scripting": "High"})
+ */
+func (gd *Godradis) SetIssueSeverities(project *Project, severities map[string]string) ([]string, error) {
+	issues, err := gd.GetAllIssues(project)
+	if err != nil {
+		return []string{}, err
+	}
+	byTitle := make(map[string]string, len(severities))
+	for title, severity := range severities {
+		byTitle[strings.ToLower(title)] = severity
+	}
+	matched := make(map[string]bool)
+	var failures []string
+	for i := range issues {
+		title := strings.ToLower(issues[i].Title)
+		severity, ok := byTitle[title]
+		if !ok {
+			continue
+		}
+		matched[title] = true
+		fields := issues[i].CopyFields()
+		fields.Set("Severity", severity)
+		err = gd.UpdateIssue(&issues[i], &fields)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("issue %v: %v", issues[i].Id, err))
+		}
+	}
+	unmatched := []string{}
+	for title := range severities {
+		if !matched[strings.ToLower(title)] {
+			unmatched = append(unmatched, title)
+		}
+	}
+	sort.Strings(unmatched)
+	if len(failures) > 0 {
+		return unmatched, errors.New(fmt.Sprintf("could not set severity of %v issues: %s", len(failures), strings.Join(failures, "; ")))
+	}
+	return unmatched, nil
+}
+
 /*
 DeleteIssue takes a reference to an existing Issue object and deletes it on the server.
+The API has no endpoint for listing or restoring deleted items, so a deleted issue can't be recovered through godradis.
 
     gd := godradis.Godradis{}
 
@@ -1090,15 +3299,15 @@ DeleteIssue takes a reference to an existing Issue object and deletes it on the
     _ := gd.DeleteIssue(&issue)
  */
 func (gd *Godradis) DeleteIssue(i *Issue) error {
-	resp, err := gd.sendRequestWithProjectId("DELETE", fmt.Sprintf("issues/%v", i.Id), i.Project.Id, nil)
+	projectId, err := i.projectId()
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode == http.StatusOK {
-		return nil
-	} else {
-		return errors.New("could not delete issue")
+	resp, err := gd.sendRequestWithProjectId("DELETE", fmt.Sprintf("issues/%v", i.Id), projectId, nil)
+	if err != nil {
+		return err
 	}
+	return gd.checkDelete(resp, "could not delete issue")
 }
 
 // Evidence endpoint
@@ -1115,7 +3324,11 @@ that node.
     evidences, _ := gd.GetAllEvidence(&node)
  */
 func (gd *Godradis) GetAllEvidence(node *Node) ([]Evidence, error) {
-	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v/evidence", node.Id), node.Project.Id, nil)
+	projectId, err := node.projectId()
+	if err != nil {
+		return []Evidence{}, err
+	}
+	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v/evidence", node.Id), projectId, nil)
 	if err != nil {
 		return []Evidence{}, err
 	}
@@ -1150,7 +3363,11 @@ GetEvidenceById takes a reference to a Node object and int id and returns the Ev
     evidence, _ := gd.GetEvidenceById(&node, 7)
  */
 func (gd *Godradis) GetEvidenceById(node *Node, id int) (Evidence, error) {
-	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v/evidence/%v", node.Id, id), node.Project.Id, nil)
+	projectId, err := node.projectId()
+	if err != nil {
+		return Evidence{}, err
+	}
+	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v/evidence/%v", node.Id, id), projectId, nil)
 	if err != nil {
 		return Evidence{}, err
 	}
@@ -1172,11 +3389,201 @@ func (gd *Godradis) GetEvidenceById(node *Node, id int) (Evidence, error) {
 	return evidence, nil
 }
 
+/*
+GetEvidenceForIssue takes a reference to an Issue object and returns every Evidence instance that references it across
+all of the nodes in the issue's project. Each Evidence keeps its Node reference.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    issue, _ := gd.GetIssueByTitle(&project, "Cross-Site Scripting")
+    evidences, _ := gd.GetEvidenceForIssue(&issue)
+    for _, evidence := range evidences {
+        fmt.Println(evidence.Node.Label)
+    }
+ */
+func (gd *Godradis) GetEvidenceForIssue(issue *Issue) ([]Evidence, error) {
+	_, err := issue.projectId()
+	if err != nil {
+		return []Evidence{}, err
+	}
+	nodes, err := gd.GetAllNodes(issue.Project, WithNotes(false))
+	if err != nil {
+		return []Evidence{}, err
+	}
+	evidences := []Evidence{}
+	for i := range nodes {
+		for _, evidence := range nodes[i].Evidence {
+			if evidence.Issue.Id == issue.Id {
+				evidences = append(evidences, evidence)
+			}
+		}
+	}
+	return evidences, nil
+}
+
+/*
+EvidenceByNodeForIssue takes a reference to a Project object and an Issue, and returns the evidence that references the
+issue grouped by the label of the node it is on. Nodes without evidence for the issue are not included in the map.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    issue, _ := gd.GetIssueByTitle(&project, "Cross-Site Scripting")
+    byNode, _ := gd.EvidenceByNodeForIssue(&project, &issue)
+    for label, evidences := range byNode {
+        fmt.Printf("%s: %v instances\n", label, len(evidences))
+    }
+ */
+func (gd *Godradis) EvidenceByNodeForIssue(project *Project, issue *Issue) (map[string][]Evidence, error) {
+	nodes, err := gd.GetAllNodes(project, WithNotes(false))
+	if err != nil {
+		return nil, err
+	}
+	byNode := make(map[string][]Evidence)
+	for i := range nodes {
+		for _, evidence := range nodes[i].Evidence {
+			if evidence.Issue.Id == issue.Id {
+				byNode[nodes[i].Label] = append(byNode[nodes[i].Label], evidence)
+			}
+		}
+	}
+	return byNode, nil
+}
+
+/*
+EvidenceBySeverity takes a reference to a Project object and returns all of the evidence in the project grouped by the
+Severity field of the issue it is attached to, with surrounding whitespace trimmed. Evidence whose issue has no Severity
+field, or whose issue no longer exists, is grouped under "". Each Evidence keeps its Node reference.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    bySeverity, _ := gd.EvidenceBySeverity(&project)
+    for _, evidence := range bySeverity["Critical"] {
+        fmt.Printf("%s: %s\n", evidence.Node.Label, evidence.Issue.Title)
+    }
+ */
+func (gd *Godradis) EvidenceBySeverity(project *Project) (map[string][]Evidence, error) {
+	issues, err := gd.GetAllIssues(project)
+	if err != nil {
+		return nil, err
+	}
+	severities := make(map[int]string, len(issues))
+	for _, issue := range issues {
+		if value, ok := issue.Fields.Get("Severity"); ok {
+			severities[issue.Id] = strings.TrimSpace(fmt.Sprintf("%v", value))
+		}
+	}
+	nodes, err := gd.GetAllNodes(project, WithNotes(false))
+	if err != nil {
+		return nil, err
+	}
+	bySeverity := make(map[string][]Evidence)
+	for i := range nodes {
+		for _, evidence := range nodes[i].Evidence {
+			severity := severities[evidence.Issue.Id]
+			bySeverity[severity] = append(bySeverity[severity], evidence)
+		}
+	}
+	return bySeverity, nil
+}
+
+/*
+GetIssueWithEvidence takes a reference to a Project object and an issue id and returns the Issue along with every
+Evidence instance that references it, combining GetIssueById and GetEvidenceForIssue.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    issue, evidences, _ := gd.GetIssueWithEvidence(&project, 12)
+ */
+func (gd *Godradis) GetIssueWithEvidence(project *Project, id int) (Issue, []Evidence, error) {
+	issue, err := gd.GetIssueById(project, id)
+	if err != nil {
+		return Issue{}, []Evidence{}, err
+	}
+	evidences, err := gd.GetEvidenceForIssue(&issue)
+	if err != nil {
+		return Issue{}, []Evidence{}, err
+	}
+	return issue, evidences, nil
+}
+
+/*
+AuditEvidenceReferences takes a reference to a Project object and returns every Evidence instance whose issue no longer
+exists in the project, which can happen after issues are deleted or merged in bulk. Each Evidence keeps its Node
+reference so it can be deleted or re-pointed with UpdateEvidence.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    orphans, _ := gd.AuditEvidenceReferences(&project)
+    for _, evidence := range orphans {
+        fmt.Printf("evidence %v on %v references missing issue %v\n", evidence.Id, evidence.Node.Label, evidence.Issue.Id)
+    }
+ */
+func (gd *Godradis) AuditEvidenceReferences(project *Project) ([]Evidence, error) {
+	issues, err := gd.GetAllIssues(project)
+	if err != nil {
+		return []Evidence{}, err
+	}
+	issueIds := make(map[int]bool)
+	for _, issue := range issues {
+		issueIds[issue.Id] = true
+	}
+	nodes, err := gd.GetAllNodes(project, WithNotes(false))
+	if err != nil {
+		return []Evidence{}, err
+	}
+	orphans := []Evidence{}
+	for i := range nodes {
+		for _, evidence := range nodes[i].Evidence {
+			if !issueIds[evidence.Issue.Id] {
+				orphans = append(orphans, evidence)
+			}
+		}
+	}
+	return orphans, nil
+}
+
+/*
+ResolveEvidenceIssue takes a reference to an Evidence object and fetches the full Issue that it is attached to, using the
+project of the evidence's node. An error is returned if the evidence has no Node reference or the node has no Project.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    evidence, _ := gd.GetEvidenceById(&node, 4)
+    issue, _ := gd.ResolveEvidenceIssue(&evidence)
+    fmt.Println(issue.Text)
+ */
+func (gd *Godradis) ResolveEvidenceIssue(evidence *Evidence) (Issue, error) {
+	_, err := evidence.projectId()
+	if err != nil {
+		return Issue{}, err
+	}
+	return gd.GetIssueById(evidence.Node.Project, evidence.Issue.Id)
+}
+
 /*
 CreateEvidence takes references to existing Node and Issue objects, and an OrderedMap object containing the content of the
 Evidence instance. The Evidence is attached to the node and issue on the Dradis server and a local Evidence object is
 returned.
 
+The Dradis API has no position for evidence, and the server lists it in creation order, so evidence that needs a specific
+order has to be created in that order.
+
     gd := godradis.Godradis{}
 
     [...]
@@ -1189,13 +3596,64 @@ returned.
     content.Set("Details", "Lorem ipsum dolor sit amet")
     evidence, _ := gd.CreateEvidence(&node, &issue, content)
  */
-func (gd *Godradis) CreateEvidence(node *Node, issue *Issue, content *orderedmap.OrderedMap) (Evidence, error) {
-	text := parseOrderedMapFields(content)
-	evidence, err := gd.CreateEvidenceFromText(node, issue, text)
+func (gd *Godradis) CreateEvidence(node *Node, issue *Issue, content *orderedmap.OrderedMap) (Evidence, error) {
+	text, err := gd.parseFields(content)
+	if err != nil {
+		return Evidence{}, err
+	}
+	evidence, err := gd.CreateEvidenceFromText(node, issue, text)
+	if err != nil {
+		return Evidence{}, err
+	}
+	return evidence, nil
+}
+
+/*
+ApplyEvidenceTemplate takes a slice of references to Node objects, a reference to an Issue object, and an OrderedMap
+containing the evidence content and creates one Evidence instance from that content on every node. A failure on one node
+does not stop the remaining nodes from being processed; the Evidence that was created successfully is returned along with
+a single error describing every node that failed.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    issue, _ := gd.GetIssueByTitle(&project, "Cross-Site Scripting")
+    content := orderedmap.New()
+    content.Set("Port", "443/tcp")
+    content.Set("Details", "Lorem ipsum dolor sit amet")
+    evidences, err := gd.ApplyEvidenceTemplate([]*godradis.Node{&node1, &node2}, &issue, content)
+ */
+func (gd *Godradis) ApplyEvidenceTemplate(nodes []*Node, issue *Issue, template *orderedmap.OrderedMap) ([]Evidence, error) {
+	return gd.ApplyEvidenceTemplateContext(context.Background(), nodes, issue, template)
+}
+
+/*
+ApplyEvidenceTemplateContext behaves like ApplyEvidenceTemplate but checks ctx before each node. If ctx is cancelled the
+remaining nodes are skipped and the Evidence created so far is returned along with ctx.Err().
+ */
+func (gd *Godradis) ApplyEvidenceTemplateContext(ctx context.Context, nodes []*Node, issue *Issue, template *orderedmap.OrderedMap) ([]Evidence, error) {
+	text, err := gd.parseFields(template)
 	if err != nil {
-		return Evidence{}, err
+		return []Evidence{}, err
 	}
-	return evidence, nil
+	var evidences []Evidence
+	var failures []string
+	for _, node := range nodes {
+		if ctx.Err() != nil {
+			return evidences, ctx.Err()
+		}
+		evidence, err := gd.CreateEvidenceFromText(node, issue, text)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("node %v: %v", node.Id, err))
+			continue
+		}
+		evidences = append(evidences, evidence)
+	}
+	if len(failures) > 0 {
+		return evidences, errors.New(fmt.Sprintf("could not create evidence on %v of %v nodes: %s", len(failures), len(nodes), strings.Join(failures, "; ")))
+	}
+	return evidences, nil
 }
 
 /*
@@ -1212,6 +3670,13 @@ string containing the raw body content and returns the newly created Evidence in
     evidence, _ := gd.CreateEvidence(&node, &issue, "#[Port]#\r\n443/tcp\r\n\r\n#[Details]#\r\nLorem ipsum dolor\r\n\r\n")
  */
 func (gd *Godradis) CreateEvidenceFromText(node *Node, issue *Issue, content string) (Evidence, error) {
+	projectId, err := node.projectId()
+	if err != nil {
+		return Evidence{}, err
+	}
+	if node.Project != nil && issue.Project != nil && node.Project.Id != issue.Project.Id {
+		return Evidence{}, errors.New(fmt.Sprintf("node %v belongs to project %v but issue %v belongs to project %v", node.Id, node.Project.Id, issue.Id, issue.Project.Id))
+	}
 	// Required so that json.Marshal() sends the fields wrapped in an evidence{} json object
 	type evidenceDetails struct {
 		Content string `json:"content"`
@@ -1225,12 +3690,15 @@ func (gd *Godradis) CreateEvidenceFromText(node *Node, issue *Issue, content str
 	if err != nil {
 		return Evidence{}, err
 	}
-	resp, err := gd.sendRequestWithProjectId("POST", fmt.Sprintf("nodes/%v/evidence", node.Id), node.Project.Id, jsonBody)
+	resp, err := gd.sendRequestWithProjectId("POST", fmt.Sprintf("nodes/%v/evidence", node.Id), projectId, jsonBody)
 	if err != nil {
 		return Evidence{}, err
 	}
 	defer resp.Body.Close()
 	var newEvidence Evidence
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return Evidence{}, parseValidationError(resp)
+	}
 	if resp.StatusCode != http.StatusCreated {
 		return Evidence{}, errors.New("could not create evidence")
 	}
@@ -1248,6 +3716,38 @@ func (gd *Godradis) CreateEvidenceFromText(node *Node, issue *Issue, content str
 	return newEvidence, nil
 }
 
+/*
+CloneEvidenceToIssues creates a copy of the evidence's content on the same node for each issue in issues, for when one
+piece of evidence supports several findings. Every issue is attempted even if some fail; the new Evidence objects are
+returned, and also added to the node's Evidence, along with an error listing the issues that failed.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    evidence, _ := gd.GetEvidenceById(&node, 2)
+    clones, err := gd.CloneEvidenceToIssues(&evidence, []*godradis.Issue{&issue1, &issue2})
+ */
+func (gd *Godradis) CloneEvidenceToIssues(evidence *Evidence, issues []*Issue) ([]Evidence, error) {
+	if evidence.Node == nil {
+		return []Evidence{}, errors.New(fmt.Sprintf("evidence %v has no Node reference", evidence.Id))
+	}
+	clones := []Evidence{}
+	var failures []string
+	for _, issue := range issues {
+		clone, err := gd.CreateEvidenceFromText(evidence.Node, issue, evidence.Content)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("issue %v: %v", issue.Id, err))
+			continue
+		}
+		clones = append(clones, clone)
+	}
+	if len(failures) > 0 {
+		return clones, errors.New(fmt.Sprintf("could not clone evidence to %v of %v issues: %s", len(failures), len(issues), strings.Join(failures, "; ")))
+	}
+	return clones, nil
+}
+
 /*
 UpdateEvidence takes a reference to an existing Evidence object, an OrderedMap containing the fields making up the content
 of the Evidence body, and optionally a reference to an Issue object if the evidence is going to be attached to a different
@@ -1266,8 +3766,10 @@ not just the fields that are being modified.
     _ := gd.UpdateEvidence(&evidence, newFields)
  */
 func (gd *Godradis) UpdateEvidence(evidence *Evidence, fields *orderedmap.OrderedMap, issue ...*Issue) error {
-	text := parseOrderedMapFields(fields)
-	var err error
+	text, err := gd.parseFields(fields)
+	if err != nil {
+		return err
+	}
 	if len(issue) > 0 {
 		err = gd.UpdateEvidenceFromText(evidence, text, issue[0])
 	} else {
@@ -1294,6 +3796,10 @@ evidence object is modified in place with the updated information.
     _ := gd.UpdateEvidenceFromText(&evidence, "#[Port]#\r\n443/tcp\r\n\r\n#[Details]#\r\nLorem ipsum dolor\r\n\r\n")
  */
 func (gd *Godradis) UpdateEvidenceFromText(evidence *Evidence, content string, issue ...*Issue) error {
+	projectId, err := evidence.projectId()
+	if err != nil {
+		return err
+	}
 	// Required so that json.Marshal() sends the fields wrapped in a evidence{} json object
 	type evidenceDetails struct {
 		Content string `json:"content"`
@@ -1312,11 +3818,14 @@ func (gd *Godradis) UpdateEvidenceFromText(evidence *Evidence, content string, i
 	if err != nil {
 		return err
 	}
-	resp, err := gd.sendRequestWithProjectId("PUT", fmt.Sprintf("nodes/%v/evidence/%v", evidence.Node.Id, evidence.Id), evidence.Node.Project.Id, jsonBody)
+	resp, err := gd.sendRequestWithProjectId("PUT", fmt.Sprintf("nodes/%v/evidence/%v", evidence.Node.Id, evidence.Id), projectId, jsonBody)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return parseValidationError(resp)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return errors.New("could not update evidence")
 	}
@@ -1344,18 +3853,22 @@ DeleteEvidence takes a reference to an existing Evidence object and deletes it o
     _ := gd.DeleteEvidence(&evidence)
  */
 func (gd *Godradis) DeleteEvidence(evidence *Evidence) error {
-	resp, err := gd.sendRequestWithProjectId("DELETE", fmt.Sprintf("nodes/%v/evidence/%v", evidence.Node.Id, evidence.Id), evidence.Node.Project.Id, nil)
+	projectId, err := evidence.projectId()
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode == http.StatusOK {
-		if evidence.Node != nil {
-			evidence.Node.deleteEvidence(*evidence)
-		}
-		return nil
-	} else {
-		return errors.New("could not delete evidence")
+	resp, err := gd.sendRequestWithProjectId("DELETE", fmt.Sprintf("nodes/%v/evidence/%v", evidence.Node.Id, evidence.Id), projectId, nil)
+	if err != nil {
+		return err
+	}
+	err = gd.checkDelete(resp, "could not delete evidence")
+	if err != nil {
+		return err
+	}
+	if evidence.Node != nil {
+		evidence.Node.deleteEvidence(*evidence)
 	}
+	return nil
 }
 
 // Notes endpoint
@@ -1371,7 +3884,11 @@ GetAllNotes takes a reference to a Node object and returns a list of all Notes a
     notes, _ := gd.GetAllNotes(&node)
  */
 func (gd *Godradis) GetAllNotes(node *Node) ([]Note, error) {
-	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v/notes", node.Id), node.Project.Id, nil)
+	projectId, err := node.projectId()
+	if err != nil {
+		return []Note{}, err
+	}
+	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v/notes", node.Id), projectId, nil)
 	if err != nil {
 		return []Note{}, err
 	}
@@ -1395,6 +3912,56 @@ func (gd *Godradis) GetAllNotes(node *Node) ([]Note, error) {
 	return notes, nil
 }
 
+/*
+GetAllNotesForProject takes a reference to a Project object and returns the notes on every node in the project. The
+nodes are listed with GetAllNodesShallow and their notes are then fetched a few nodes at a time. Each Note's Node
+reference points to a node whose Notes are also filled in. The notes are returned grouped by node, in node order. If any
+node's notes can't be fetched, the notes of the other nodes are returned along with an error describing the failures.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    notes, _ := gd.GetAllNotesForProject(&project)
+    for _, note := range notes {
+        fmt.Printf("%s: %s\n", note.Node.Label, note.Title)
+    }
+ */
+func (gd *Godradis) GetAllNotesForProject(project *Project) ([]Note, error) {
+	nodes, err := gd.GetAllNodesShallow(project)
+	if err != nil {
+		return []Note{}, err
+	}
+	errs := make([]error, len(nodes))
+	sem := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i := range nodes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			nodes[i].Notes, errs[i] = gd.GetAllNotes(&nodes[i])
+		}(i)
+	}
+	wg.Wait()
+
+	notes := []Note{}
+	var failures []string
+	for i := range nodes {
+		if errs[i] != nil {
+			failures = append(failures, fmt.Sprintf("node %v: %v", nodes[i].Id, errs[i]))
+			continue
+		}
+		notes = append(notes, nodes[i].Notes...)
+	}
+	if len(failures) > 0 {
+		return notes, errors.New(fmt.Sprintf("could not get notes on %v of %v nodes: %s", len(failures), len(nodes), strings.Join(failures, "; ")))
+	}
+	return notes, nil
+}
+
 /*
 GetNoteById takes a reference to a Node object and int id and returns the Note instance associated with that id.
 
@@ -1406,7 +3973,11 @@ GetNoteById takes a reference to a Node object and int id and returns the Note i
     note, _ := gd.GetNoteById(&node, 7)
  */
 func (gd *Godradis) GetNoteById(node *Node, id int) (Note, error) {
-	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v/notes/%v", node.Id, id), node.Project.Id, nil)
+	projectId, err := node.projectId()
+	if err != nil {
+		return Note{}, err
+	}
+	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v/notes/%v", node.Id, id), projectId, nil)
 	if err != nil {
 		return Note{}, err
 	}
@@ -1445,7 +4016,7 @@ func (gd *Godradis) GetNoteByTitle(node *Node, title string) (Note, error) {
 		return Note{}, err
 	}
 	for _, note := range notes {
-		if strings.ToLower(note.Title) == strings.ToLower(title) {
+		if gd.namesMatch(note.Title, title) {
 			return note, nil
 		}
 	}
@@ -1457,6 +4028,8 @@ CreateNote takes a reference to an existing Node object, an OrderedMap object co
 Note, and an optional integer category ID that sets the note category (Defaults to "Default Category" in Dradis). The Note
 is attached to the node on the Dradis server and a local Note object is returned.
 
+As with evidence, the Dradis API has no position for notes and the server lists them in creation order.
+
     gd := godradis.Godradis{}
 
     [...]
@@ -1467,7 +4040,10 @@ is attached to the node on the Dradis server and a local Note object is returned
     note, _ := gd.CreateNote(&node, fields)
  */
 func (gd *Godradis) CreateNote(node *Node, fields *orderedmap.OrderedMap, categoryId ...int) (Note, error) {
-	text := parseOrderedMapFields(fields)
+	text, err := gd.parseFields(fields)
+	if err != nil {
+		return Note{}, err
+	}
 	var cid int
 	if len(categoryId) > 0 {
 		cid = categoryId[0]
@@ -1481,6 +4057,63 @@ func (gd *Godradis) CreateNote(node *Node, fields *orderedmap.OrderedMap, catego
 	return note, nil
 }
 
+/*
+CreateNoteWithCategory behaves like CreateNote but takes the name of the note category instead of its ID. The name is
+resolved with GetNoteCategoryByName and an error is returned without creating the note if no category matches.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
+    fields := orderedmap.New()
+    fields.Set("Hostnames", "foo.com\r\nexample.foo.com")
+    note, _ := gd.CreateNoteWithCategory(&node, fields, "Default category")
+ */
+func (gd *Godradis) CreateNoteWithCategory(node *Node, fields *orderedmap.OrderedMap, categoryName string) (Note, error) {
+	category, err := gd.GetNoteCategoryByName(categoryName)
+	if err != nil {
+		return Note{}, err
+	}
+	return gd.CreateNote(node, fields, category.Id)
+}
+
+/*
+CreateNotes takes a reference to an existing Node object and a slice of NoteInput, and creates a Note on the node for each
+input with its own category. Every input is attempted even if some fail; the notes that were created are returned, and
+also added to node.Notes, along with an error listing the inputs that failed.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
+    hostnames := orderedmap.New()
+    hostnames.Set("Hostnames", "foo.com\r\nexample.foo.com")
+    ports := orderedmap.New()
+    ports.Set("Open Ports", "22/tcp\r\n443/tcp")
+    notes, err := gd.CreateNotes(&node, []godradis.NoteInput{
+        {Fields: hostnames, CategoryId: 6},
+        {Fields: ports, CategoryId: 7},
+    })
+ */
+func (gd *Godradis) CreateNotes(node *Node, notes []NoteInput) ([]Note, error) {
+	created := []Note{}
+	var failures []string
+	for i, input := range notes {
+		note, err := gd.CreateNote(node, input.Fields, input.CategoryId)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("note %v: %v", i, err))
+			continue
+		}
+		created = append(created, note)
+	}
+	if len(failures) > 0 {
+		return created, errors.New(fmt.Sprintf("could not create %v of %v notes: %s", len(failures), len(notes), strings.Join(failures, "; ")))
+	}
+	return created, nil
+}
+
 /*
 CreateNoteFromText takes a reference to an existing Node object, a string containing the body of the Note, and an optional
 integer category ID that sets the note category (Defaults to "Default Category" in Dradis). The Note is attached to the
@@ -1495,6 +4128,10 @@ node on the Dradis server and a local Note object is returned.
     note, _ := gd.CreateNote(&node, text)
  */
 func (gd *Godradis) CreateNoteFromText(node *Node, text string, categoryId ...int) (Note, error) {
+	projectId, err := node.projectId()
+	if err != nil {
+		return Note{}, err
+	}
 	// Required so that json.Marshal() sends the fields wrapped in an note{} json object
 	type noteDetails struct {
 		Text string `json:"text"`
@@ -1515,12 +4152,15 @@ func (gd *Godradis) CreateNoteFromText(node *Node, text string, categoryId ...in
 	if err != nil {
 		return Note{}, err
 	}
-	resp, err := gd.sendRequestWithProjectId("POST", fmt.Sprintf("nodes/%v/notes", node.Id), node.Project.Id, jsonBody)
+	resp, err := gd.sendRequestWithProjectId("POST", fmt.Sprintf("nodes/%v/notes", node.Id), projectId, jsonBody)
 	if err != nil {
 		return Note{}, err
 	}
 	defer resp.Body.Close()
 	var newNote Note
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return Note{}, parseValidationError(resp)
+	}
 	if resp.StatusCode != http.StatusCreated {
 		return Note{}, errors.New("could not create note")
 	}
@@ -1533,131 +4173,300 @@ func (gd *Godradis) CreateNoteFromText(node *Node, text string, categoryId ...in
 	if err != nil {
 		return Note{}, err
 	}
-	newNote.Node = node
-	node.addNote(newNote)
-	return newNote, nil
+	newNote.Node = node
+	node.addNote(newNote)
+	return newNote, nil
+}
+
+/*
+UpdateNote takes a reference to an existing Note object, an OrderedMap containing the fields making up the content
+of the Note body, and an optional integer category ID that sets the note category (Defaults to "Default Category" in Dradis).
+UpdateNote updates the note on the server and modifies the local Note object in place with the updated information. Note
+that due to the way the Dradis API works, all fields in the body must be passed in the OrderedMap, not just the fields that
+are being modified.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
+    note, _ := gd.GetNoteByTitle(&node, "Nmap Host Info")
+    newFields := note.CopyFields()
+    newFields.Set("Hostnames", "sub.foo.com")
+    _ := gd.UpdateNote(&note, newFields)
+ */
+func (gd *Godradis) UpdateNote(note *Note, fields *orderedmap.OrderedMap, categoryId ...int) error {
+	text, err := gd.parseFields(fields)
+	if err != nil {
+		return err
+	}
+	if len(categoryId) > 0 {
+		err = gd.UpdateNoteFromText(note, text, categoryId[0])
+	} else {
+		err = gd.UpdateNoteFromText(note, text)
+	}
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+/*
+UpdateNoteFromText takes a reference to an existing Note object, a string containing the body of the Note, and an optional
+integer category ID that sets the note category (Defaults to "Default Category" in Dradis). UpdateNoteFromText updates the
+note on the server and modifies the local Note object in place with the updated information.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
+    note, _ := gd.GetNoteByTitle(&node, "Nmap Host Info")
+    text := "#[Hostnames]#\r\nsub.foo.com\r\n\r\n
+    note, _ := gd.UpdateNoteFromText(&node, text)
+ */
+func (gd *Godradis) UpdateNoteFromText(note *Note, text string, categoryId ...int) error {
+	projectId, err := note.projectId()
+	if err != nil {
+		return err
+	}
+	// Required so that json.Marshal() sends the fields wrapped in a note{} json object
+	type noteDetails struct {
+		Text string `json:"text,omitempty"`
+		CategoryId string `json:"category_id,omitempty"`
+	}
+	type reqModel struct {
+		NoteDetails noteDetails `json:"note"`
+	}
+
+	nd := noteDetails{}
+	nd.Text = text
+	if len(categoryId) > 0 {
+		nd.CategoryId = strconv.Itoa(categoryId[0])
+	}
+
+	jsonBody, err := json.Marshal(&reqModel{nd})
+	if err != nil {
+		return err
+	}
+	resp, err := gd.sendRequestWithProjectId("PUT", fmt.Sprintf("nodes/%v/notes/%v", note.Node.Id, note.Id), projectId, jsonBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return parseValidationError(resp)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("could not update note")
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(body, &note)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+/*
+DeleteNote takes a reference to an existing Note object and deletes it on the server.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
+    note, _ := gd.GetNoteByTitle(&node, "Nmap Host Info")
+    _ := gd.DeleteNote(&note)
+ */
+func (gd *Godradis) DeleteNote(note *Note) error {
+	projectId, err := note.projectId()
+	if err != nil {
+		return err
+	}
+	resp, err := gd.sendRequestWithProjectId("DELETE", fmt.Sprintf("nodes/%v/notes/%v", note.Node.Id, note.Id), projectId, nil)
+	if err != nil {
+		return err
+	}
+	err = gd.checkDelete(resp, "could not delete note")
+	if err != nil {
+		return err
+	}
+	if note.Node != nil {
+		note.Node.deleteNote(*note)
+	}
+	return nil
+}
+
+// Note categories endpoint
+
+/*
+GetAllNoteCategories takes no arguments and returns a list of all note categories on the server.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    categories, _ := gd.GetAllNoteCategories()
+    for _, category := range categories {
+        fmt.Printf("%v: %v\n", category.Id, category.Name)
+    }
+ */
+func (gd *Godradis) GetAllNoteCategories() ([]NoteCategory, error) {
+	resp, err := gd.sendRequest("GET", "categories", nil)
+	if err != nil {
+		return []NoteCategory{}, err
+	}
+	defer resp.Body.Close()
+	var categories []NoteCategory
+	if resp.StatusCode != http.StatusOK {
+		return []NoteCategory{}, errors.New("could not get note category list")
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return []NoteCategory{}, err
+	}
+
+	err = json.Unmarshal(body, &categories)
+	if err != nil {
+		return []NoteCategory{}, err
+	}
+	return categories, nil
 }
 
 /*
-UpdateNote takes a reference to an existing Note object, an OrderedMap containing the fields making up the content
-of the Note body, and an optional integer category ID that sets the note category (Defaults to "Default Category" in Dradis).
-UpdateNote updates the note on the server and modifies the local Note object in place with the updated information. Note
-that due to the way the Dradis API works, all fields in the body must be passed in the OrderedMap, not just the fields that
-are being modified.
+GetNoteCategoryByName searches for and returns a NoteCategory object based on the name, compared case-insensitively
+unless Config.CaseSensitiveNames is set. The categories are looked up with CachedNoteCategories, so call RefreshMetadata
+first if they may have changed on the server. The error for an unknown name lists the categories that do exist.
 
     gd := godradis.Godradis{}
 
     [...]
 
-    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
-    note, _ := gd.GetNoteByTitle(&node, "Nmap Host Info")
-    newFields := note.CopyFields()
-    newFields.Set("Hostnames", "sub.foo.com")
-    _ := gd.UpdateNote(&note, newFields)
+    category, err := gd.GetNoteCategoryByName("Default category")
  */
-func (gd *Godradis) UpdateNote(note *Note, fields *orderedmap.OrderedMap, categoryId ...int) error {
-	text := parseOrderedMapFields(fields)
-	var err error
-	if len(categoryId) > 0 {
-		err = gd.UpdateNoteFromText(note, text, categoryId[0])
-	} else {
-		err = gd.UpdateNoteFromText(note, text)
-	}
-
+func (gd *Godradis) GetNoteCategoryByName(name string) (NoteCategory, error) {
+	categories, err := gd.CachedNoteCategories()
 	if err != nil {
-		return err
+		return NoteCategory{}, err
 	}
-	return nil
+	var names []string
+	for _, category := range categories {
+		if gd.namesMatch(category.Name, name) {
+			return category, nil
+		}
+		names = append(names, category.Name)
+	}
+	return NoteCategory{}, errors.New(fmt.Sprintf("could not find note category %s (available: %s)", name, strings.Join(names, ", ")))
 }
 
 /*
-UpdateNoteFromText takes a reference to an existing Note object, a string containing the body of the Note, and an optional
-integer category ID that sets the note category (Defaults to "Default Category" in Dradis). UpdateNoteFromText updates the
-note on the server and modifies the local Note object in place with the updated information.
+ResolveNoteCategory returns the name of the note's category. The categories are looked up with CachedNoteCategories, and
+an error is returned if the note's CategoryId doesn't match any of them.
 
     gd := godradis.Godradis{}
 
     [...]
 
-    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
     note, _ := gd.GetNoteByTitle(&node, "Nmap Host Info")
-    text := "#[Hostnames]#\r\nsub.foo.com\r\n\r\n
-    note, _ := gd.UpdateNoteFromText(&node, text)
+    category, _ := gd.ResolveNoteCategory(&note)
+    fmt.Printf("%s (%s)\n", note.Title, category)
  */
-func (gd *Godradis) UpdateNoteFromText(note *Note, text string, categoryId ...int) error {
-	// Required so that json.Marshal() sends the fields wrapped in a note{} json object
-	type noteDetails struct {
-		Text string `json:"text,omitempty"`
-		CategoryId string `json:"category_id,omitempty"`
+func (gd *Godradis) ResolveNoteCategory(note *Note) (string, error) {
+	categories, err := gd.CachedNoteCategories()
+	if err != nil {
+		return "", err
 	}
-	type reqModel struct {
-		NoteDetails noteDetails `json:"note"`
+	for _, category := range categories {
+		if category.Id == note.CategoryId {
+			return category.Name, nil
+		}
 	}
+	return "", errors.New(fmt.Sprintf("could not find note category with id %v", note.CategoryId))
+}
 
-	nd := noteDetails{}
-	nd.Text = text
-	if len(categoryId) > 0 {
-		nd.CategoryId = strconv.Itoa(categoryId[0])
-	}
+// Content blocks endpoint
 
-	jsonBody, err := json.Marshal(&reqModel{nd})
-	if err != nil {
-		return err
-	}
-	resp, err := gd.sendRequestWithProjectId("PUT", fmt.Sprintf("nodes/%v/notes/%v", note.Node.Id, note.Id), note.Node.Project.Id, jsonBody)
+/*
+GetAllContentBlocks takes a reference to a Project object and returns all of the project's content blocks.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    blocks, _ := gd.GetAllContentBlocks(&project)
+ */
+func (gd *Godradis) GetAllContentBlocks(project *Project) ([]ContentBlock, error) {
+	resp, err := gd.sendRequestWithProjectId("GET", "content_blocks", project.Id, nil)
 	if err != nil {
-		return err
+		return []ContentBlock{}, err
 	}
 	defer resp.Body.Close()
+	var blocks []ContentBlock
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("could not update note")
+		return []ContentBlock{}, errors.New("could not get content block list")
 	}
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return []ContentBlock{}, err
 	}
 
-	err = json.Unmarshal(body, &note)
+	err = json.Unmarshal(body, &blocks)
 	if err != nil {
-		return err
+		return []ContentBlock{}, err
 	}
-	return nil
+	for i := range blocks {
+		blocks[i].Project = project
+	}
+	return blocks, nil
 }
 
 /*
-DeleteNote takes a reference to an existing Note object and deletes it on the server.
+GetContentBlocksByGroup takes a reference to a Project object and returns the project's content blocks keyed by their
+block group. Blocks without a group are keyed by "". Within each group the blocks keep the order the server returned.
 
     gd := godradis.Godradis{}
 
     [...]
 
-    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
-    note, _ := gd.GetNoteByTitle(&node, "Nmap Host Info")
-    _ := gd.DeleteNote(&note)
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    groups, _ := gd.GetContentBlocksByGroup(&project)
+    for _, block := range groups["Conclusions"] {
+        fmt.Println(block.Title)
+    }
  */
-func (gd *Godradis) DeleteNote(note *Note) error {
-	resp, err := gd.sendRequestWithProjectId("DELETE", fmt.Sprintf("nodes/%v/notes/%v", note.Node.Id, note.Id), note.Node.Project.Id, nil)
+func (gd *Godradis) GetContentBlocksByGroup(project *Project) (map[string][]ContentBlock, error) {
+	blocks, err := gd.GetAllContentBlocks(project)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if resp.StatusCode == http.StatusOK {
-		if note.Node != nil {
-			note.Node.deleteNote(*note)
-		}
-		return nil
-	} else {
-		return errors.New("could not delete note")
+	groups := make(map[string][]ContentBlock)
+	for _, block := range blocks {
+		group := strings.TrimSpace(block.BlockGroup)
+		groups[group] = append(groups[group], block)
 	}
+	return groups, nil
 }
 
 // Attachments endpoint
 
 /*
 GetAllAttachments takes a reference to an existing Node object and returns a slice of all attachments associated with that
-node.
+node. Size and ContentType are filled in from the server's listing; use GetAttachmentMetadata for attachments whose
+listing leaves them empty.
  */
 func (gd *Godradis) GetAllAttachments(node *Node) ([]Attachment, error) {
-	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v/attachments", node.Id), node.Project.Id, nil)
+	projectId, err := node.projectId()
+	if err != nil {
+		return []Attachment{}, err
+	}
+	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v/attachments", node.Id), projectId, nil)
 	if err != nil {
 		return []Attachment{}, err
 	}
@@ -1681,13 +4490,62 @@ func (gd *Godradis) GetAllAttachments(node *Node) ([]Attachment, error) {
 	return attachments, nil
 }
 
+/*
+GetAttachmentMetadata sets the Size and ContentType of an attachment from a HEAD request on its DownloadURL, for servers
+whose attachment listing doesn't include them. The API key is only sent if the link is on the same host as
+Config.BaseUrl. The attachment is left as it is and an error returned if the request fails or the server answers with an
+HTML page, which usually means the link led to the login page.
+
+    attachments, _ := gd.GetAllAttachments(&node)
+    for i := range attachments {
+        if attachments[i].ContentType == "" {
+            err := gd.GetAttachmentMetadata(&attachments[i])
+        }
+    }
+ */
+func (gd *Godradis) GetAttachmentMetadata(attachment *Attachment) error {
+	if attachment.Link == "" {
+		return errors.New(fmt.Sprintf("attachment %s has no link", attachment.Filename))
+	}
+	req, err := http.NewRequest("HEAD", attachment.DownloadURL(gd), nil)
+	if err != nil {
+		return err
+	}
+	base, err := url.Parse(gd.Config.BaseUrl)
+	if err == nil && strings.EqualFold(req.URL.Host, base.Host) {
+		req.Header.Add("Authorization", authHeader(gd.apiKey()))
+	}
+	// The response describes the file rather than being JSON, so doRequest's content type check doesn't apply
+	resp, err := gd.doRequestWithRetries(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(fmt.Sprintf("could not get metadata for attachment %s: status %v", attachment.Filename, resp.StatusCode))
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if strings.HasPrefix(strings.ToLower(contentType), "text/html") {
+		return errors.Wrap(ErrUnauthorized, fmt.Sprintf("could not get metadata for attachment %s", attachment.Filename))
+	}
+	if resp.ContentLength > 0 {
+		attachment.Size = resp.ContentLength
+	}
+	attachment.ContentType = contentType
+	return nil
+}
+
 /*
 GetAttachmentByName takes a reference to an existing Node object and a string filename and returns an Attachment object
 if it is found on the server.
  */
 func (gd *Godradis) GetAttachmentByName(node *Node, filename string) (Attachment, error) {
+	projectId, err := node.projectId()
+	if err != nil {
+		return Attachment{}, err
+	}
 	escapedFilename := url.PathEscape(filename)
-	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v/attachments/%v", node.Id, escapedFilename), node.Project.Id, nil)
+	resp, err := gd.sendRequestWithProjectId("GET", fmt.Sprintf("nodes/%v/attachments/%v", node.Id, escapedFilename), projectId, nil)
 	if err != nil {
 		return Attachment{}, err
 	}
@@ -1709,11 +4567,86 @@ func (gd *Godradis) GetAttachmentByName(node *Node, filename string) (Attachment
 	return attachment, nil
 }
 
+/*
+AttachmentStats takes a reference to a Project object and returns the number of attachments across all of its nodes and
+their total size in bytes. The nodes' attachments are listed a few at a time. Attachments whose size the server doesn't
+report count towards the total as 0 bytes. If any node's attachments can't be listed, the totals for the other nodes are
+returned along with an error describing the failures.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    count, size, _ := gd.AttachmentStats(&project)
+    fmt.Printf("%v attachments, %v bytes\n", count, size)
+ */
+func (gd *Godradis) AttachmentStats(project *Project) (int, int64, error) {
+	nodes, err := gd.GetAllNodesShallow(project)
+	if err != nil {
+		return 0, 0, err
+	}
+	attachments := make([][]Attachment, len(nodes))
+	errs := make([]error, len(nodes))
+	sem := make(chan struct{}, maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i := range nodes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			attachments[i], errs[i] = gd.GetAllAttachments(&nodes[i])
+		}(i)
+	}
+	wg.Wait()
+
+	count := 0
+	var totalBytes int64
+	var failures []string
+	for i := range nodes {
+		if errs[i] != nil {
+			failures = append(failures, fmt.Sprintf("node %v: %v", nodes[i].Id, errs[i]))
+			continue
+		}
+		for _, attachment := range attachments[i] {
+			count++
+			totalBytes += attachment.Size
+		}
+	}
+	if len(failures) > 0 {
+		return count, totalBytes, errors.New(fmt.Sprintf("could not list attachments on %v of %v nodes: %s", len(failures), len(nodes), strings.Join(failures, "; ")))
+	}
+	return count, totalBytes, nil
+}
+
 /*
 UploadAttachments takes a reference to an existing Node object and a slice of strings containing filepaths and uploads
-these attachments to the Dradis server. A slice of Attachment objects is returned.
+these attachments to the Dradis server. A slice of Attachment objects is returned. An optional UploadProgressFunc is
+called as the request body is sent.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    attachments, _ := gd.UploadAttachments(&node, []string{"/tmp/evidence.zip"}, func(sent, total int64) {
+        fmt.Printf("\r%v/%v bytes", sent, total)
+    })
  */
-func (gd *Godradis) UploadAttachments(node *Node, filePath []string) ([]Attachment, error) {
+func (gd *Godradis) UploadAttachments(node *Node, filePath []string, progress ...UploadProgressFunc) ([]Attachment, error) {
+	return gd.UploadAttachmentsContext(context.Background(), node, filePath, progress...)
+}
+
+/*
+UploadAttachmentsContext behaves like UploadAttachments but sends the upload with ctx, so a slow upload can be abandoned
+by cancelling ctx. The configured Timeout and retries apply as for any other request; the files are read into memory
+before sending, so a retried upload sends the same body again.
+ */
+func (gd *Godradis) UploadAttachmentsContext(ctx context.Context, node *Node, filePath []string, progress ...UploadProgressFunc) ([]Attachment, error) {
+	projectId, err := node.projectId()
+	if err != nil {
+		return []Attachment{}, err
+	}
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 	for _, path := range filePath {
@@ -1728,16 +4661,24 @@ func (gd *Godradis) UploadAttachments(node *Node, filePath []string) ([]Attachme
 		}
 		_, err = io.Copy(part, file)
 		file.Close()
+		if err != nil {
+			return []Attachment{}, err
+		}
 	}
-	err := writer.Close()
+	err = writer.Close()
 	if err != nil {
 		return nil, err
 	}
-	req, _ := http.NewRequest("POST", fmt.Sprintf("%s/pro/api/nodes/%v/attachments", gd.Config.BaseUrl, node.Id), body)
-	req.Header.Add("Authorization", fmt.Sprintf(`Token token="%s"`, gd.Config.ApiKey))
-	req.Header.Set("Dradis-Project-Id", strconv.Itoa(node.Project.Id))
+	req := gd.newRequest("POST", fmt.Sprintf("nodes/%v/attachments", node.Id), body.Bytes()).WithContext(ctx)
+	if err = gd.checkProjectDeleted(projectId); err != nil {
+		return []Attachment{}, err
+	}
+	req.Header.Set("Dradis-Project-Id", strconv.Itoa(projectId))
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	resp, err := gd.httpClient.Do(req)
+	if len(progress) > 0 && progress[0] != nil {
+		trackProgress(req, progress[0])
+	}
+	resp, err := gd.doRequest(req)
 	if err != nil {
 		return []Attachment{}, err
 	}
@@ -1761,26 +4702,123 @@ func (gd *Godradis) UploadAttachments(node *Node, filePath []string) ([]Attachme
 	return attachments, nil
 }
 
+/*
+UploadAttachmentsIfMissing behaves like UploadAttachments but first lists the node's existing attachments and only uploads
+the files whose base name isn't already attached, so re-running an import doesn't create duplicates. The uploaded
+Attachment objects are returned along with the paths that were skipped.
+ */
+func (gd *Godradis) UploadAttachmentsIfMissing(node *Node, filePath []string) ([]Attachment, []string, error) {
+	existing, err := gd.GetAllAttachments(node)
+	if err != nil {
+		return []Attachment{}, []string{}, err
+	}
+	existingNames := make(map[string]bool, len(existing))
+	for _, attachment := range existing {
+		existingNames[attachment.Filename] = true
+	}
+	var missing []string
+	skipped := []string{}
+	for _, path := range filePath {
+		if existingNames[filepath.Base(path)] {
+			skipped = append(skipped, path)
+		} else {
+			missing = append(missing, path)
+		}
+	}
+	if len(missing) == 0 {
+		return []Attachment{}, skipped, nil
+	}
+	uploaded, err := gd.UploadAttachments(node, missing)
+	if err != nil {
+		return []Attachment{}, skipped, err
+	}
+	return uploaded, skipped, nil
+}
+
+/*
+AttachScreenshotToEvidence uploads the image at imagePath to node, adds the Dradis markup that embeds it to the Screenshot
+field of a copy of fields (after any existing value), and creates Evidence on node for issue from the result. fields may
+be nil. The uploaded Attachment and the new Evidence are returned. If the evidence can't be created the attachment is
+left on the node and returned along with the error.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    fields := orderedmap.New()
+    fields.Set("Port", "443/tcp")
+    attachment, evidence, _ := gd.AttachScreenshotToEvidence(&node, &issue, "/tmp/xss.png", fields)
+ */
+func (gd *Godradis) AttachScreenshotToEvidence(node *Node, issue *Issue, imagePath string, fields *orderedmap.OrderedMap) (Attachment, Evidence, error) {
+	attachments, err := gd.UploadAttachments(node, []string{imagePath})
+	if err != nil {
+		return Attachment{}, Evidence{}, err
+	}
+	if len(attachments) == 0 {
+		return Attachment{}, Evidence{}, errors.New("could not upload attachment: server returned no attachments")
+	}
+	attachment := attachments[0]
+	markup, err := attachment.markup()
+	if err != nil {
+		return attachment, Evidence{}, err
+	}
+
+	content := orderedmap.New()
+	if fields != nil {
+		for _, k := range fields.Keys() {
+			v, _ := fields.Get(k)
+			content.Set(k, v)
+		}
+	}
+	if existing, ok := content.Get("Screenshot"); ok && fmt.Sprintf("%v", existing) != "" {
+		markup = fmt.Sprintf("%v\r\n\r\n%s", existing, markup)
+	}
+	content.Set("Screenshot", markup)
+
+	evidence, err := gd.CreateEvidence(node, issue, content)
+	if err != nil {
+		return attachment, Evidence{}, err
+	}
+	return attachment, evidence, nil
+}
+
 /*
 DeleteAttachment takes a reference to an existing Attachment object and deletes it from the server. The local Attachment
 object reference is set to nil.
  */
 func (gd *Godradis) DeleteAttachment(attachment *Attachment) error {
-	resp, err := gd.sendRequestWithProjectId("DELETE", fmt.Sprintf("nodes/%v/attachments/%v", attachment.Node.Id, attachment.Filename), attachment.Node.Project.Id, nil)
+	projectId, err := attachment.projectId()
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode == http.StatusOK {
-		return nil
-	} else {
-		return errors.New("could not delete attachment")
+	resp, err := gd.sendRequestWithProjectId("DELETE", fmt.Sprintf("nodes/%v/attachments/%v", attachment.Node.Id, attachment.Filename), projectId, nil)
+	if err != nil {
+		return err
 	}
+	return gd.checkDelete(resp, "could not delete attachment")
 }
 
 // IssueLibEntry endpoint
 
 func (gd *Godradis) GetIssueLibrary() ([]IssueLibEntry, error) {
-	resp, err := gd.sendRequest("GET", "addons/issuelib/entries", nil)
+	return gd.getIssueLibrary(0)
+}
+
+// GetProjectIssueLibrary lists the issue library with the Dradis-Project-Id header set to the project's ID, for Dradis
+// versions that scope library entries per project. Use GetIssueLibrary for the global library.
+func (gd *Godradis) GetProjectIssueLibrary(project *Project) ([]IssueLibEntry, error) {
+	return gd.getIssueLibrary(project.Id)
+}
+
+// getIssueLibrary lists the issue library entries. projectId is omitted from the request if it is 0.
+func (gd *Godradis) getIssueLibrary(projectId int) ([]IssueLibEntry, error) {
+	var resp *http.Response
+	var err error
+	if projectId != 0 {
+		resp, err = gd.sendRequestWithProjectId("GET", "addons/issuelib/entries", projectId, nil)
+	} else {
+		resp, err = gd.sendRequest("GET", "addons/issuelib/entries", nil)
+	}
 	if err != nil {
 		return []IssueLibEntry{}, err
 	}
@@ -1823,25 +4861,34 @@ func (gd *Godradis) GetIssueLibraryById(id int) (IssueLibEntry, error) {
 	return issueLib, nil
 }
 
-func (gd *Godradis) CreateIssueLibraryEntry(fields *orderedmap.OrderedMap) (IssueLibEntry, error) {
-	text := parseOrderedMapFields(fields)
-	entry, err := gd.CreateIssueLibraryEntryFromText(text)
+// CreateIssueLibraryEntry creates a library entry from fields. An optional state sets the entry's IssueLibState.
+func (gd *Godradis) CreateIssueLibraryEntry(fields *orderedmap.OrderedMap, state ...IssueLibState) (IssueLibEntry, error) {
+	text, err := gd.parseFields(fields)
+	if err != nil {
+		return IssueLibEntry{}, err
+	}
+	entry, err := gd.CreateIssueLibraryEntryFromText(text, state...)
 	if err != nil {
 		return IssueLibEntry{}, err
 	}
 	return entry, nil
 }
 
-func (gd *Godradis) CreateIssueLibraryEntryFromText(content string) (IssueLibEntry, error) {
+// CreateIssueLibraryEntryFromText creates a library entry from raw content. An optional state sets the entry's IssueLibState.
+func (gd *Godradis) CreateIssueLibraryEntryFromText(content string, state ...IssueLibState) (IssueLibEntry, error) {
 	// Required so that json.Marshal() sends the fields wrapped in an entry{} json object
 	type entryDetails struct {
 		Content string `json:"content"`
+		State *IssueLibState `json:"state,omitempty"`
 	}
 	type reqModel struct {
 		EntryDetails entryDetails `json:"entry"`
 	}
 	ed := entryDetails{}
 	ed.Content = content
+	if len(state) > 0 {
+		ed.State = &state[0]
+	}
 
 	jsonBody, err := json.Marshal(&reqModel{ed})
 	if err != nil {
@@ -1853,6 +4900,9 @@ func (gd *Godradis) CreateIssueLibraryEntryFromText(content string) (IssueLibEnt
 	}
 	defer resp.Body.Close()
 	var newEntry IssueLibEntry
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return IssueLibEntry{}, parseValidationError(resp)
+	}
 	if resp.StatusCode != http.StatusCreated {
 		return IssueLibEntry{}, errors.New("could not create issuelib entry")
 	}
@@ -1868,25 +4918,83 @@ func (gd *Godradis) CreateIssueLibraryEntryFromText(content string) (IssueLibEnt
 	return newEntry, nil
 }
 
-func (gd *Godradis) UpdateIssueLibraryEntry(entry *IssueLibEntry, fields *orderedmap.OrderedMap) error {
-	text := parseOrderedMapFields(fields)
-	err := gd.UpdateIssueLibraryEntryFromText(entry, text)
+// CopyIssueLibraryEntry creates a new library entry with the same fields as source, except for the Title field which is
+// set to newTitle.
+func (gd *Godradis) CopyIssueLibraryEntry(source *IssueLibEntry, newTitle string) (IssueLibEntry, error) {
+	fields := source.CopyFields()
+	fields.Set("Title", newTitle)
+	entry, err := gd.CreateIssueLibraryEntry(&fields)
+	if err != nil {
+		return IssueLibEntry{}, err
+	}
+	return entry, nil
+}
+
+// CopyIssueLibraryTo recreates every entry in this server's issue library on dst, keeping each entry's content and state.
+// Entries whose title already exists on dst, compared case-insensitively, are skipped. Every entry is attempted even if
+// some fail; the entries created on dst are returned along with an error listing the ones that failed.
+func (gd *Godradis) CopyIssueLibraryTo(dst *Godradis) ([]IssueLibEntry, error) {
+	entries, err := gd.GetIssueLibrary()
+	if err != nil {
+		return []IssueLibEntry{}, err
+	}
+	existing, err := dst.GetIssueLibrary()
+	if err != nil {
+		return []IssueLibEntry{}, err
+	}
+	titles := make(map[string]bool)
+	for _, entry := range existing {
+		titles[strings.ToLower(entry.Title)] = true
+	}
+	created := []IssueLibEntry{}
+	var failures []string
+	for _, entry := range entries {
+		if titles[strings.ToLower(entry.Title)] {
+			continue
+		}
+		newEntry, err := dst.CreateIssueLibraryEntryFromText(entry.Content, entry.State)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("entry %v (%s): %v", entry.Id, entry.Title, err))
+			continue
+		}
+		titles[strings.ToLower(entry.Title)] = true
+		created = append(created, newEntry)
+	}
+	if len(failures) > 0 {
+		return created, errors.New(fmt.Sprintf("could not copy %v of %v issue library entries: %s", len(failures), len(entries), strings.Join(failures, "; ")))
+	}
+	return created, nil
+}
+
+// UpdateIssueLibraryEntry updates a library entry from fields. An optional state changes the entry's IssueLibState.
+func (gd *Godradis) UpdateIssueLibraryEntry(entry *IssueLibEntry, fields *orderedmap.OrderedMap, state ...IssueLibState) error {
+	text, err := gd.parseFields(fields)
+	if err != nil {
+		return err
+	}
+	err = gd.UpdateIssueLibraryEntryFromText(entry, text, state...)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (gd *Godradis) UpdateIssueLibraryEntryFromText(entry *IssueLibEntry, content string) error {
+// UpdateIssueLibraryEntryFromText updates a library entry from raw content. An optional state changes the entry's
+// IssueLibState.
+func (gd *Godradis) UpdateIssueLibraryEntryFromText(entry *IssueLibEntry, content string, state ...IssueLibState) error {
 	// Required so that json.Marshal() sends the fields wrapped in an entry{} json object
 	type entryDetails struct {
 		Content string `json:"content"`
+		State *IssueLibState `json:"state,omitempty"`
 	}
 	type reqModel struct {
 		EntryDetails entryDetails `json:"entry"`
 	}
 	ed := entryDetails{}
 	ed.Content = content
+	if len(state) > 0 {
+		ed.State = &state[0]
+	}
 	jsonBody, err := json.Marshal(&reqModel{ed})
 	if err != nil {
 		return err
@@ -1896,6 +5004,9 @@ func (gd *Godradis) UpdateIssueLibraryEntryFromText(entry *IssueLibEntry, conten
 		return err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return parseValidationError(resp)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return errors.New("could not update issuelib entry")
 	}
@@ -1915,9 +5026,95 @@ func (gd *Godradis) DeleteIssueLibraryById(entry IssueLibEntry) error {
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode == http.StatusOK {
-		return nil
-	} else {
-		return errors.New("could not delete issue library entry")
+	return gd.checkDelete(resp, "could not delete issue library entry")
+}
+
+// Raw requests
+
+/*
+Raw is an escape hatch for endpoints that godradis doesn't model yet. It sends an authenticated request for resource
+(relative to "/pro/api/") with the given method and JSON body and returns the raw response body and HTTP status code. If
+projectId is non-nil it is sent as the Dradis-Project-Id header. Non-2xx statuses are not treated as errors so that the
+caller can inspect the response, and the body is returned as it is whatever its content type.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    projectId := 45
+    body, status, err := gd.Raw("GET", "nodes/12/evidence", &projectId, nil)
+    if err == nil && status == http.StatusOK {
+        fmt.Println(string(body))
+    }
+ */
+func (gd *Godradis) Raw(method, resource string, projectId *int, body []byte) ([]byte, int, error) {
+	req := gd.newRequest(method, resource, body)
+	if projectId != nil {
+		if err := gd.checkProjectDeleted(*projectId); err != nil {
+			return nil, 0, err
+		}
+		req.Header.Set("Dradis-Project-Id", strconv.Itoa(*projectId))
 	}
-}
\ No newline at end of file
+	resp, err := gd.doRequestWithRetries(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	return respBody, resp.StatusCode, nil
+}
+
+/*
+RawInProject behaves like Raw but takes the Dradis-Project-Id header from project, for project-scoped endpoints.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    body, status, err := gd.RawInProject(&project, "GET", "nodes/12/evidence", nil)
+ */
+func (gd *Godradis) RawInProject(project *Project, method, resource string, body []byte) ([]byte, int, error) {
+	if project == nil {
+		return nil, 0, errors.New("RawInProject requires a project")
+	}
+	return gd.Raw(method, resource, &project.Id, body)
+}
+
+/*
+GetReader sends an authenticated GET request for resource (relative to "/pro/api/") and returns the response body as a
+stream along with the HTTP status code, so that large downloads such as generated reports don't have to be held in memory.
+If projectId is non-nil it is sent as the Dradis-Project-Id header. As with Raw, the response may have any content type
+and non-2xx statuses are not treated as errors. The caller must close the returned body.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    projectId := 45
+    body, status, err := gd.GetReader("exports/12/download", &projectId)
+    if err == nil {
+        defer body.Close()
+        if status == http.StatusOK {
+            f, _ := os.Create("report.docx")
+            io.Copy(f, body)
+        }
+    }
+ */
+func (gd *Godradis) GetReader(resource string, projectId *int) (io.ReadCloser, int, error) {
+	req := gd.newRequest("GET", resource, nil)
+	if projectId != nil {
+		if err := gd.checkProjectDeleted(*projectId); err != nil {
+			return nil, 0, err
+		}
+		req.Header.Set("Dradis-Project-Id", strconv.Itoa(*projectId))
+	}
+	resp, err := gd.doRequestWithRetries(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Body, resp.StatusCode, nil
+}
@@ -0,0 +1,319 @@
+package godradis
+
+import (
+	"fmt"
+	"github.com/iancoleman/orderedmap"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// newTestClient starts a server with handler and returns a Godradis configured to talk to it
+func newTestClient(t testing.TB, handler http.Handler) (*Godradis, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	gd := &Godradis{}
+	gd.Configure(server.URL, "test-key", true)
+	return gd, server
+}
+
+// fakeDradis answers requests with canned JSON bodies keyed by "METHOD /path" (without the "/pro/api" prefix) and records
+// every request it receives. POST requests are answered with 201 and everything else with 200; unknown routes get a 404.
+type fakeDradis struct {
+	mu sync.Mutex
+	routes map[string]string
+	requests []string
+	bodies map[string]string
+}
+
+func newFakeDradis(routes map[string]string) *fakeDradis {
+	return &fakeDradis{routes: routes, bodies: make(map[string]string)}
+}
+
+func (f *fakeDradis) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route := fmt.Sprintf("%s %s", r.Method, r.URL.Path[len("/pro/api"):])
+	body, _ := ioutil.ReadAll(r.Body)
+	f.mu.Lock()
+	f.requests = append(f.requests, route)
+	f.bodies[route] = string(body)
+	resp, ok := f.routes[route]
+	f.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "POST" {
+		w.WriteHeader(http.StatusCreated)
+	}
+	w.Write([]byte(resp))
+}
+
+// setRoute replaces the canned body for route, e.g. to simulate another client changing an object on the server
+func (f *fakeDradis) setRoute(route, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.routes[route] = body
+}
+
+// sent returns the requests received so far that were not GETs, in order
+func (f *fakeDradis) sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var sent []string
+	for _, route := range f.requests {
+		if route[:4] != "GET " {
+			sent = append(sent, route)
+		}
+	}
+	return sent
+}
+
+func TestReauthFunc(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != `Token token="fresh-key"` {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	calls := 0
+	gd.Config.ReauthFunc = func() (string, error) {
+		calls++
+		return "fresh-key", nil
+	}
+	body, status, err := gd.Raw("GET", "teams", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusOK || string(body) != "[]" {
+		t.Errorf("got %v %s", status, body)
+	}
+	if calls != 1 || gd.Config.ApiKey != "fresh-key" {
+		t.Errorf("got %v ReauthFunc calls and key %q", calls, gd.Config.ApiKey)
+	}
+}
+
+func TestReauthFuncConcurrent(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != `Token token="fresh-key"` {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	var calls int32
+	gd.Config.ReauthFunc = func() (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return "fresh-key", nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, status, err := gd.Raw("GET", "teams", nil, nil)
+			if err != nil || status != http.StatusOK {
+				t.Errorf("got %v %v", status, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("ReauthFunc called %v times, want 1", calls)
+	}
+}
+
+func TestContentTypeCheck(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body string
+		wantErr error
+	}{
+		{"text/html; charset=utf-8", "<html><body>Sign in</body></html>", ErrUnauthorized},
+		{"text/plain", "[]", ErrUnexpectedContentType},
+		{"application/json; charset=utf-8", "[]", nil},
+		{"", "[]", nil},
+	}
+	for _, tt := range tests {
+		gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tt.contentType == "" {
+				// Stops net/http from sniffing a content type
+				w.Header()["Content-Type"] = nil
+			} else {
+				w.Header().Set("Content-Type", tt.contentType)
+			}
+			w.Write([]byte(tt.body))
+		}))
+		_, err := gd.GetAllTeams()
+		if errors.Cause(err) != tt.wantErr {
+			t.Errorf("%q: got error %v, want %v", tt.contentType, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMissingBackReferences(t *testing.T) {
+	fake := newFakeDradis(map[string]string{})
+	gd, _ := newTestClient(t, fake)
+	orphanNode := &Node{Id: 1}
+	orphanIssue := &Issue{Id: 2}
+	evidenceWithoutNode := &Evidence{Id: 3}
+	evidenceWithOrphanNode := &Evidence{Id: 4, Node: orphanNode}
+	noteWithoutNode := &Note{Id: 5}
+	attachmentWithoutNode := &Attachment{Filename: "shot.png"}
+	fields := orderedmap.New()
+	calls := []struct {
+		name string
+		wantErr string
+		call func() error
+	}{
+		{"UpdateNode", "node 1 has no Project reference", func() error { return gd.UpdateNode(orphanNode, "x", nil, nil, nil) }},
+		{"MoveNode", "node 1 has no Project reference", func() error { return gd.MoveNode(orphanNode, 0) }},
+		{"DeleteNode", "node 1 has no Project reference", func() error { return gd.DeleteNode(orphanNode) }},
+		{"RefreshNode", "node 1 has no Project reference", func() error { return gd.RefreshNode(orphanNode) }},
+		{"GetAllEvidence", "node 1 has no Project reference", func() error { _, err := gd.GetAllEvidence(orphanNode); return err }},
+		{"GetAllNotes", "node 1 has no Project reference", func() error { _, err := gd.GetAllNotes(orphanNode); return err }},
+		{"CreateNoteFromText", "node 1 has no Project reference", func() error { _, err := gd.CreateNoteFromText(orphanNode, "x"); return err }},
+		{"GetAllAttachments", "node 1 has no Project reference", func() error { _, err := gd.GetAllAttachments(orphanNode); return err }},
+		{"UpdateIssueIfUnchanged", "issue 2 has no Project reference", func() error { return gd.UpdateIssueIfUnchanged(orphanIssue, fields) }},
+		{"DeleteIssue", "issue 2 has no Project reference", func() error { return gd.DeleteIssue(orphanIssue) }},
+		{"GetEvidenceForIssue", "issue 2 has no Project reference", func() error { _, err := gd.GetEvidenceForIssue(orphanIssue); return err }},
+		{"RefreshIssue", "issue 2 has no Project reference", func() error { return gd.RefreshIssue(orphanIssue) }},
+		{"UpdateEvidenceFromText", "evidence 3 has no Node reference", func() error { return gd.UpdateEvidenceFromText(evidenceWithoutNode, "x") }},
+		{"DeleteEvidence", "evidence 3 has no Node reference", func() error { return gd.DeleteEvidence(evidenceWithoutNode) }},
+		{"ResolveEvidenceIssue", "evidence 3 has no Node reference", func() error { _, err := gd.ResolveEvidenceIssue(evidenceWithoutNode); return err }},
+		{"CloneEvidenceToIssues", "evidence 3 has no Node reference", func() error { _, err := gd.CloneEvidenceToIssues(evidenceWithoutNode, nil); return err }},
+		{"DeleteEvidence with an orphan node", "node 1 has no Project reference", func() error { return gd.DeleteEvidence(evidenceWithOrphanNode) }},
+		{"UpdateNoteFromText", "note 5 has no Node reference", func() error { return gd.UpdateNoteFromText(noteWithoutNode, "x") }},
+		{"DeleteNote", "note 5 has no Node reference", func() error { return gd.DeleteNote(noteWithoutNode) }},
+		{"RefreshNote", "note 5 has no Node reference", func() error { return gd.RefreshNote(noteWithoutNode) }},
+		{"DeleteAttachment", "attachment shot.png has no Node reference", func() error { return gd.DeleteAttachment(attachmentWithoutNode) }},
+	}
+	for _, c := range calls {
+		err := c.call()
+		if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+			t.Errorf("%s: got error %v, want %q", c.name, err, c.wantErr)
+		}
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("unexpected requests %v", fake.requests)
+	}
+}
+
+func TestReconnectOnError(t *testing.T) {
+	var requests int32
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Drop the connection without a response the first time, as a restarting server would
+		if atomic.AddInt32(&requests, 1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	_, _, err := gd.Raw("GET", "teams", nil, nil)
+	if err == nil {
+		t.Fatal("expected a transport error without ReconnectOnError")
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	gd.Config.ReconnectOnError = true
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, status, err := gd.Raw("GET", "teams", nil, nil)
+			if err != nil || status != http.StatusOK || string(body) != "[]" {
+				t.Errorf("got %v %s %v", status, body, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestReconnectOnErrorResendsBody(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		first := len(bodies) == 1
+		mu.Unlock()
+		if first {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	gd.Config.ReconnectOnError = true
+	sent := `{"node":{"label":"10.0.0.1"}}`
+	if _, _, err := gd.Raw("PUT", "nodes/1", nil, []byte(sent)); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(bodies, []string{sent, sent}) {
+		t.Errorf("got bodies %q, want the body sent twice", bodies)
+	}
+}
+
+func TestReconnectOnErrorDoesNotResendPost(t *testing.T) {
+	var requests int32
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	gd.Config.ReconnectOnError = true
+	if _, _, err := gd.Raw("POST", "issues", nil, []byte(`{}`)); err == nil {
+		t.Fatal("expected a transport error")
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("POST sent %v times, want 1", n)
+	}
+}
+
+func TestStrictDeletes(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		strict bool
+		wantErr string
+	}{
+		{"lenient ignores an error body", `{"error": "Record not found"}`, false, ""},
+		{"strict error body", `{"error": "Record not found"}`, true, "could not delete issue: Record not found"},
+		{"strict errors body", `{"errors": ["Issue not found"]}`, true, "could not delete issue: [Issue not found]"},
+		{"strict failure message", `{"message": "Nothing to delete"}`, true, "could not delete issue: Nothing to delete"},
+		{"strict success message", `{"message": "Resource deleted successfully"}`, true, ""},
+		{"strict empty body", ``, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gd, _ := newTestClient(t, newFakeDradis(map[string]string{"DELETE /issues/2": tt.body}))
+			gd.Config.StrictDeletes = tt.strict
+			err := gd.DeleteIssue(&Issue{Id: 2, Project: &Project{Id: 1}})
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("got error %v, want none", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package godradis
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// HostInfo is the structured host information that Node.HostInfo reads from a host node's notes.
+type HostInfo struct {
+	OS string
+	IPAddresses []string
+	Hostnames []string
+}
+
+// HostFieldNames lists the note field names that Node.HostInfo reads each part of HostInfo from. Names are compared
+// case-insensitively.
+type HostFieldNames struct {
+	OS []string
+	IPAddresses []string
+	Hostnames []string
+}
+
+// DefaultHostFieldNames holds the field names that HostInfo looks for when none are passed.
+var DefaultHostFieldNames = HostFieldNames{
+	OS: []string{"OS", "Operating System"},
+	IPAddresses: []string{"IP", "IP Address", "IP Addresses", "Addresses"},
+	Hostnames: []string{"Hostname", "Hostnames"},
+}
+
+/*
+HostInfo reads the OS, IP addresses and hostnames of a host node from the fields of its notes, using DefaultHostFieldNames
+unless names is passed. The OS is taken from the first note that has one. IP addresses and hostnames are collected from
+every note, split on newlines and commas, with duplicates removed. If the node's label is an IP address it is included as
+well. Only the node's in-memory Notes are read, so the node should come from GetAllNodes or GetNodeById.
+
+    node, _ := gd.GetNodeByLabel(&project, "10.0.0.1")
+    host := node.HostInfo()
+    fmt.Printf("%s: %s\n", strings.Join(host.Hostnames, ", "), host.OS)
+ */
+func (n *Node) HostInfo(names ...HostFieldNames) HostInfo {
+	fieldNames := DefaultHostFieldNames
+	if len(names) > 0 {
+		fieldNames = names[0]
+	}
+	info := HostInfo{IPAddresses: []string{}, Hostnames: []string{}}
+	if net.ParseIP(n.Label) != nil {
+		info.IPAddresses = append(info.IPAddresses, n.Label)
+	}
+	for i := range n.Notes {
+		fields := &n.Notes[i].Fields
+		for _, k := range fields.Keys() {
+			v, _ := fields.Get(k)
+			value := strings.TrimSpace(fmt.Sprintf("%v", v))
+			switch {
+			case hasFieldName(fieldNames.OS, k):
+				if info.OS == "" {
+					info.OS = value
+				}
+			case hasFieldName(fieldNames.IPAddresses, k):
+				info.IPAddresses = appendUnique(info.IPAddresses, splitHostValues(value))
+			case hasFieldName(fieldNames.Hostnames, k):
+				info.Hostnames = appendUnique(info.Hostnames, splitHostValues(value))
+			}
+		}
+	}
+	return info
+}
+
+func hasFieldName(names []string, key string) bool {
+	for _, name := range names {
+		if strings.ToLower(name) == strings.ToLower(strings.TrimSpace(key)) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitHostValues splits a field value listing several addresses or hostnames, one per line or separated by commas
+func splitHostValues(value string) []string {
+	var values []string
+	for _, v := range strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || r == '\r' || r == ',' }) {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func appendUnique(existing []string, values []string) []string {
+	for _, v := range values {
+		found := false
+		for _, e := range existing {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, v)
+		}
+	}
+	return existing
+}
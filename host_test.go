@@ -0,0 +1,35 @@
+package godradis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHostInfo(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /nodes/2": `{"id": 2, "label": "10.0.0.1", "type_id": 1, "notes": [
+			{"id": 1, "fields": {"Title": "Nmap host info", "Operating System": "Linux 4.15",
+				"Hostnames": "web01.example.com\r\nwww.example.com", "ip addresses": "10.0.0.1, 2001:db8::1"}},
+			{"id": 2, "fields": {"Title": "Manual", "OS": "Ubuntu 18.04", "Hostname": "www.example.com, api.example.com",
+				"Platform": "Ubuntu 18.04 LTS", "Aliases": "web01"}}
+		]}`,
+	}))
+	node, err := gd.GetNodeById(&Project{Id: 1}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := HostInfo{
+		OS: "Linux 4.15",
+		IPAddresses: []string{"10.0.0.1", "2001:db8::1"},
+		Hostnames: []string{"web01.example.com", "www.example.com", "api.example.com"},
+	}
+	if got := node.HostInfo(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	custom := HostFieldNames{OS: []string{"Platform"}, Hostnames: []string{"Aliases"}}
+	want = HostInfo{OS: "Ubuntu 18.04 LTS", IPAddresses: []string{"10.0.0.1"}, Hostnames: []string{"web01"}}
+	if got := node.HostInfo(custom); !reflect.DeepEqual(got, want) {
+		t.Errorf("with custom names got %+v, want %+v", got, want)
+	}
+}
@@ -1,6 +1,13 @@
 package godradis
 
-import "github.com/iancoleman/orderedmap"
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/iancoleman/orderedmap"
+	"github.com/pkg/errors"
+	"strings"
+	"time"
+)
 
 type Issue struct {
 	Id int `json:"id"`
@@ -11,3 +18,97 @@ type Issue struct {
 	UpdatedAt string `json:"updated_at"`
 	Project *Project
 }
+
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings, and the fields as either an object
+// or an array of key/value pairs.
+func (i *Issue) UnmarshalJSON(data []byte) error {
+	type issue Issue
+	aux := struct {
+		*issue
+		Id flexInt `json:"id"`
+		Fields flexFields `json:"fields"`
+	}{issue: (*issue)(i), Id: flexInt(i.Id), Fields: flexFields(i.Fields)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	i.Id = int(aux.Id)
+	i.Fields = orderedmap.OrderedMap(aux.Fields)
+	return nil
+}
+
+// PlainContent returns the issue's field values in order, separated by blank lines, without the "#[Key]#" markers.
+func (i *Issue) PlainContent() string {
+	return plainContent(&i.Fields, i.Text)
+}
+
+func (i *Issue) CopyFields() orderedmap.OrderedMap {
+	fields := orderedmap.New()
+	keys := i.Fields.Keys()
+	for _, k := range keys {
+		value, ok := i.Fields.Get(k)
+		if ok {
+			fields.Set(k, value)
+		}
+	}
+	return *fields
+}
+
+// CreatedTime parses CreatedAt into a time.Time.
+func (i *Issue) CreatedTime() (time.Time, error) {
+	return parseTimestamp(i.CreatedAt)
+}
+
+// UpdatedTime parses UpdatedAt into a time.Time.
+func (i *Issue) UpdatedTime() (time.Time, error) {
+	return parseTimestamp(i.UpdatedAt)
+}
+
+// projectId returns the ID of the issue's project, or an error if the issue was built without a Project reference
+func (i *Issue) projectId() (int, error) {
+	if i.Project == nil {
+		return 0, errors.New(fmt.Sprintf("issue %v has no Project reference", i.Id))
+	}
+	return i.Project.Id, nil
+}
+
+// Tags returns the comma-separated tags in the issue's Tags field, or an empty slice if the issue has no Tags field.
+func (i *Issue) Tags() []string {
+	tags := []string{}
+	value, ok := i.Fields.Get("Tags")
+	if !ok {
+		return tags
+	}
+	for _, tag := range strings.Split(fmt.Sprintf("%v", value), ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// HasTag reports whether tag is one of the issue's Tags.
+func (i *Issue) HasTag(tag string) bool {
+	for _, t := range i.Tags() {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueSetDiff is the result of DiffProjectsIssues.
+type IssueSetDiff struct {
+	Added []Issue
+	Removed []Issue
+	Changed []IssueChange
+}
+
+// IssueChange is an issue present in both projects compared by DiffProjectsIssues. Fields holds the names of the fields
+// whose values differ.
+type IssueChange struct {
+	Before Issue
+	After Issue
+	Fields []string
+}
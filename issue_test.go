@@ -0,0 +1,503 @@
+package godradis
+
+import (
+	"fmt"
+	"github.com/iancoleman/orderedmap"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIssuesIterator(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Write([]byte(`[{"id": 1, "title": "XSS"}, {"id": 2, "title": "SQLi"}]`))
+		case "2":
+			w.Write([]byte(`[{"id": 3, "title": "CSRF"}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	project := Project{Id: 1}
+	var titles []string
+	err := gd.IssuesIterator(&project, func(issue *Issue) error {
+		if issue.Project != &project {
+			t.Errorf("issue %v has no project reference", issue.Id)
+		}
+		titles = append(titles, issue.Title)
+		if issue.Title == "SQLi" {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(titles) != 2 {
+		t.Errorf("got %v, want iteration to stop after SQLi", titles)
+	}
+
+	count := 0
+	err = gd.IssuesIterator(&project, func(issue *Issue) error {
+		count++
+		return nil
+	})
+	if err != nil || count != 3 {
+		t.Errorf("got %v issues and error %v", count, err)
+	}
+}
+
+func TestNormalizeSeverities(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /issues": `[
+			{"id": 1, "title": "XSS", "fields": {"Title": "XSS", "Severity": "HIGH"}},
+			{"id": 2, "title": "SQLi", "fields": {"Title": "SQLi", "Severity": "High"}},
+			{"id": 3, "title": "CSRF", "fields": {"Title": "CSRF", "Severity": "med"}},
+			{"id": 4, "title": "Banner", "fields": {"Title": "Banner"}}
+		]`,
+		"PUT /issues/1": `{"id": 1, "title": "XSS", "fields": {"Title": "XSS", "Severity": "High"}}`,
+		"PUT /issues/3": `{"id": 3, "title": "CSRF", "fields": {"Title": "CSRF", "Severity": "Medium"}}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	changed, err := gd.NormalizeSeverities(&project, map[string]string{"high": "High", "med": "Medium"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 2 || !reflect.DeepEqual(fake.sent(), []string{"PUT /issues/1", "PUT /issues/3"}) {
+		t.Errorf("changed %v issues with requests %v", changed, fake.sent())
+	}
+	if body := fake.bodies["PUT /issues/1"]; !strings.Contains(body, `#[Severity]#\r\nHigh`) {
+		t.Errorf("sent %s", body)
+	}
+}
+
+func TestNormalizeSeveritiesRejectsCaseCollisions(t *testing.T) {
+	fake := newFakeDradis(map[string]string{"GET /issues": `[]`})
+	gd, _ := newTestClient(t, fake)
+	_, err := gd.NormalizeSeverities(&Project{Id: 1}, map[string]string{"high": "High", "HIGH": "Critical"})
+	if err == nil {
+		t.Fatal("expected an error for labels differing only by case")
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("unexpected requests %v", fake.requests)
+	}
+	if _, err = gd.NormalizeSeverities(&Project{Id: 1}, map[string]string{"high": "High", "HIGH": "High"}); err != nil {
+		t.Errorf("got %v for labels mapping to the same value", err)
+	}
+}
+
+func TestGetOrCreateIssue(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /issues": `[{"id": 1, "title": "Cross-Site Scripting"}]`,
+		"POST /issues": `{"id": 2, "title": "SQL Injection"}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	fields := orderedmap.New()
+	fields.Set("Title", "SQL Injection")
+
+	issue, created, err := gd.GetOrCreateIssue(&project, "cross-site scripting", fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || issue.Id != 1 {
+		t.Errorf("got issue %v, created %v, want the existing issue 1", issue.Id, created)
+	}
+	issue, created, err = gd.GetOrCreateIssue(&project, "SQL Injection", fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created || issue.Id != 2 || issue.Project != &project {
+		t.Errorf("got issue %v, created %v, want the new issue 2", issue.Id, created)
+	}
+	if body := fake.bodies["POST /issues"]; !strings.Contains(body, `#[Title]#\r\nSQL Injection`) {
+		t.Errorf("got body %s", body)
+	}
+
+	gd.Config.CaseSensitiveNames = true
+	_, created, err = gd.GetOrCreateIssue(&project, "cross-site scripting", fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created {
+		t.Error("expected a new issue when titles only match case-insensitively and CaseSensitiveNames is set")
+	}
+}
+
+// Node 1 has evidence for issues 1 (twice) and 2, node 2 for issue 2 only, and node 3 has none
+const testIssueEvidence = `[
+	{"id": 1, "label": "10.0.0.1", "evidence": [
+		{"id": 11, "content": "#[Port]#\n80", "issue": {"id": 1}},
+		{"id": 12, "content": "#[Port]#\n443", "issue": {"id": 1}},
+		{"id": 13, "content": "#[Port]#\n22", "issue": {"id": 2}}
+	]},
+	{"id": 2, "label": "10.0.0.2", "evidence": [{"id": 21, "content": "#[Port]#\n22", "issue": {"id": 2}}]},
+	{"id": 3, "label": "10.0.0.3", "evidence": []}
+]`
+
+func TestIssuesByNode(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /nodes": testIssueEvidence,
+		"GET /issues": `[{"id": 1, "title": "XSS"}, {"id": 2, "title": "SSH Weak Ciphers"}, {"id": 3, "title": "Unused"}]`,
+	}))
+	byNode, err := gd.IssuesByNode(&Project{Id: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := make(map[int][]string)
+	for nodeId, issues := range byNode {
+		for _, issue := range issues {
+			titles[nodeId] = append(titles[nodeId], issue.Title)
+		}
+	}
+	want := map[int][]string{1: {"XSS", "SSH Weak Ciphers"}, 2: {"SSH Weak Ciphers"}}
+	if !reflect.DeepEqual(titles, want) {
+		t.Errorf("got %v, want %v", titles, want)
+	}
+}
+
+func TestEvidenceCountByIssue(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{"GET /nodes": testIssueEvidence}))
+	counts, err := gd.EvidenceCountByIssue(&Project{Id: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[int]int{1: 2, 2: 2}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("got %v, want %v", counts, want)
+	}
+}
+
+func TestGetIssueWithEvidence(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /issues/2": `{"id": 2, "title": "SSH Weak Ciphers"}`,
+		"GET /nodes": testIssueEvidence,
+	}))
+	project := Project{Id: 1}
+	issue, evidence, err := gd.GetIssueWithEvidence(&project, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Id != 2 || issue.Project != &project {
+		t.Errorf("got issue %v with project %p", issue.Id, issue.Project)
+	}
+	var got []string
+	for _, e := range evidence {
+		if e.Node == nil {
+			t.Errorf("evidence %v has no node reference", e.Id)
+			continue
+		}
+		got = append(got, fmt.Sprintf("%v@%s", e.Id, e.Node.Label))
+	}
+	if want := []string{"13@10.0.0.1", "21@10.0.0.2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got evidence %v, want %v", got, want)
+	}
+}
+
+func TestDistinctIssueFieldKeys(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /issues": `[
+			{"id": 1, "title": "XSS", "fields": {"Title": "XSS", "Severity": "High", "Description": "..."}},
+			{"id": 2, "title": "SQLi", "fields": {"Title": "SQLi", "CVSSv3": "9.8", "Severity": "Critical"}},
+			{"id": 3, "title": "CSRF", "fields": [{"key": "Title", "value": "CSRF"}, {"key": "severity", "value": "Low"}]},
+			{"id": 4, "title": "Empty", "fields": null}
+		]`,
+	}))
+	keys, err := gd.DistinctIssueFieldKeys(&Project{Id: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Keys are compared exactly, so the lower-case severity shows up as inconsistent naming
+	if want := []string{"Title", "Severity", "Description", "CVSSv3", "severity"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("got %v, want %v", keys, want)
+	}
+}
+
+func TestTagIssues(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"PUT /issues/1": `{"id": 1, "title": "XSS", "fields": {"Title": "XSS", "Tags": "critical"}}`,
+		"PUT /issues/2": `{"id": 2, "title": "SQLi", "fields": {"Title": "SQLi", "Tags": "web, critical"}}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	newIssue := func(id int, title, tags string) *Issue {
+		issue := &Issue{Id: id, Title: title, Fields: *orderedmap.New(), Project: &project}
+		issue.Fields.Set("Title", title)
+		if tags != "" {
+			issue.Fields.Set("Tags", tags)
+		}
+		return issue
+	}
+	issues := []*Issue{newIssue(1, "XSS", ""), newIssue(2, "SQLi", "web"), newIssue(3, "CSRF", "low, critical")}
+	if err := gd.TagIssues(issues, "critical"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"PUT /issues/1", "PUT /issues/2"}; !reflect.DeepEqual(fake.sent(), want) {
+		t.Errorf("got requests %v, want %v", fake.sent(), want)
+	}
+	if body := fake.bodies["PUT /issues/2"]; !strings.Contains(body, `#[Tags]#\r\nweb, critical`) {
+		t.Errorf("got body %s", body)
+	}
+	for _, issue := range issues {
+		if !issue.HasTag("critical") {
+			t.Errorf("issue %v has tags %v", issue.Id, issue.Tags())
+		}
+	}
+
+	// Re-tagging is a no-op, and failures are reported per issue
+	if err := gd.TagIssues(issues, "critical"); err != nil {
+		t.Fatal(err)
+	}
+	err := gd.TagIssues([]*Issue{issues[0], newIssue(4, "RCE", "")}, "web")
+	if err == nil || !strings.Contains(err.Error(), "could not tag 1 of 2 issues: issue 4") {
+		t.Errorf("got error %v", err)
+	}
+	if n := len(fake.sent()); n != 4 {
+		t.Errorf("got %v requests, want 4", n)
+	}
+}
+
+func TestSearchIssuesAcrossProjects(t *testing.T) {
+	issuesByProject := map[string]string{
+		"1": `[{"id": 1, "title": "Reflected Cross-Site Scripting"}, {"id": 2, "title": "SQL Injection"}]`,
+		"2": `[{"id": 3, "title": "Weak TLS"}]`,
+		"3": `[{"id": 4, "title": "Stored cross-site scripting"}]`,
+	}
+	var inFlight, maxInFlight int32
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/pro/api/projects" {
+			w.Write([]byte(`[{"id": 1, "name": "A"}, {"id": 2, "name": "B"}, {"id": 3, "name": "C"}, {"id": 4, "name": "D"},
+				{"id": 5, "name": "E"}, {"id": 6, "name": "F"}]`))
+			return
+		}
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		projectId := r.Header.Get("Dradis-Project-Id")
+		if projectId == "5" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, ok := issuesByProject[projectId]
+		if !ok {
+			body = `[]`
+		}
+		w.Write([]byte(body))
+	}))
+	issues, err := gd.SearchIssuesAcrossProjects("cross-site")
+	if err == nil || !strings.Contains(err.Error(), "could not search 1 of 6 projects: project 5") {
+		t.Errorf("got error %v", err)
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, fmt.Sprintf("%s: %s", issue.Project.Name, issue.Title))
+	}
+	want := []string{"A: Reflected Cross-Site Scripting", "C: Stored cross-site scripting"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > maxConcurrentRequests {
+		t.Errorf("got %v concurrent requests, want at most %v", max, maxConcurrentRequests)
+	}
+}
+
+func TestUpdateIssueIfUnchanged(t *testing.T) {
+	tests := []struct {
+		name string
+		serverUpdatedAt string
+		wantErr error
+		wantSent []string
+	}{
+		{"unchanged", "2021-06-01T12:00:00.000Z", nil, []string{"PUT /issues/1"}},
+		{"same instant, other zone", "2021-06-01T13:00:00.000+01:00", nil, []string{"PUT /issues/1"}},
+		{"changed", "2021-06-01T12:05:00.000Z", ErrConflict, nil},
+	}
+	for _, tt := range tests {
+		fake := newFakeDradis(map[string]string{
+			"GET /issues/1": `{"id": 1, "title": "XSS", "updated_at": "` + tt.serverUpdatedAt + `"}`,
+			"PUT /issues/1": `{"id": 1, "title": "XSS", "updated_at": "2021-06-02T00:00:00.000Z"}`,
+		})
+		gd, _ := newTestClient(t, fake)
+		issue := Issue{Id: 1, Title: "XSS", UpdatedAt: "2021-06-01T12:00:00.000Z", Project: &Project{Id: 1}}
+		fields := orderedmap.New()
+		fields.Set("Title", "XSS")
+		fields.Set("Severity", "Medium")
+		err := gd.UpdateIssueIfUnchanged(&issue, fields)
+		if err != tt.wantErr {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if sent := fake.sent(); !reflect.DeepEqual(sent, tt.wantSent) {
+			t.Errorf("%s: got requests %v, want %v", tt.name, sent, tt.wantSent)
+		}
+	}
+}
+
+func TestGetIssuesByTagFromServer(t *testing.T) {
+	var query string
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("tag")
+		// Ignores the tag parameter and returns every issue, so the local filter has to do the work
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"id": 1, "title": "XSS", "fields": {"Title": "XSS", "Tags": "!d62728_critical, web"}},
+			{"id": 2, "title": "SQLi", "fields": {"Title": "SQLi", "Tags": "web"}},
+			{"id": 3, "title": "CSRF", "fields": {"Title": "CSRF"}}
+		]`))
+	}))
+	project := Project{Id: 1}
+	issues, err := gd.GetIssuesByTagFromServer(&project, "!d62728_critical")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "!d62728_critical" {
+		t.Errorf("got tag parameter %q", query)
+	}
+	if len(issues) != 1 || issues[0].Id != 1 || issues[0].Project != &project {
+		t.Errorf("got %+v, want only issue 1", issues)
+	}
+}
+
+func TestGetIssuesWithoutEvidence(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /nodes": testIssueEvidence,
+		"GET /issues": `[{"id": 1, "title": "XSS"}, {"id": 2, "title": "SSH Weak Ciphers"}, {"id": 3, "title": "Unused"},
+			{"id": 4, "title": "Also unused"}]`,
+	}))
+	project := Project{Id: 1}
+	issues, err := gd.GetIssuesWithoutEvidence(&project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, issue := range issues {
+		titles = append(titles, issue.Title)
+	}
+	if want := []string{"Unused", "Also unused"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("got %v, want %v", titles, want)
+	}
+
+	gd, _ = newTestClient(t, newFakeDradis(map[string]string{"GET /nodes": testIssueEvidence, "GET /issues": `[]`}))
+	issues, err = gd.GetIssuesWithoutEvidence(&project)
+	if err != nil || issues == nil || len(issues) != 0 {
+		t.Errorf("got issues %v and error %v, want an empty slice", issues, err)
+	}
+}
+
+func TestGetIssuesUpdatedSince(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /issues": `[
+			{"id": 1, "title": "Before", "updated_at": "2021-03-01T09:59:59.000Z"},
+			{"id": 2, "title": "At", "updated_at": "2021-03-01T10:00:00.000Z"},
+			{"id": 3, "title": "After", "updated_at": "2021-03-01T10:00:00.001Z"},
+			{"id": 4, "title": "After in another zone", "updated_at": "2021-03-01T11:30:00.000+01:00"}
+		]`,
+	}))
+	since := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	issues, err := gd.GetIssuesUpdatedSince(&Project{Id: 1}, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, issue := range issues {
+		titles = append(titles, issue.Title)
+	}
+	// The boundary is exclusive, so the issue updated exactly at since is left out
+	if want := []string{"After", "After in another zone"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("got %v, want %v", titles, want)
+	}
+
+	gd, _ = newTestClient(t, newFakeDradis(map[string]string{
+		"GET /issues": `[{"id": 1, "title": "XSS", "updated_at": "yesterday"}]`,
+	}))
+	if _, err = gd.GetIssuesUpdatedSince(&Project{Id: 1}, since); err == nil || !strings.Contains(err.Error(), "yesterday") {
+		t.Errorf("got error %v for an unparseable timestamp", err)
+	}
+}
+
+func TestDiffProjectsIssues(t *testing.T) {
+	issuesByProject := map[string]string{
+		"1": `[
+			{"id": 1, "title": "XSS", "fields": {"Title": "XSS", "Severity": "High", "Description": "Reflected"}},
+			{"id": 2, "title": "SQLi", "fields": {"Title": "SQLi", "Severity": "Critical"}},
+			{"id": 3, "title": "Weak TLS", "fields": {"Title": "Weak TLS", "Severity": "Low"}}
+		]`,
+		"2": `[
+			{"id": 11, "title": "xss", "fields": {"Title": "xss", "Description": "Reflected", "Severity": "Medium"}},
+			{"id": 12, "title": "SQLi", "fields": {"Severity": "Critical", "Title": "SQLi"}},
+			{"id": 13, "title": "CSRF", "fields": {"Title": "CSRF", "Severity": "Medium"}}
+		]`,
+	}
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(issuesByProject[r.Header.Get("Dradis-Project-Id")]))
+	}))
+	diff, err := gd.DiffProjectsIssues(&Project{Id: 1}, &Project{Id: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].Id != 13 {
+		t.Errorf("got added %+v, want CSRF", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Id != 3 {
+		t.Errorf("got removed %+v, want Weak TLS", diff.Removed)
+	}
+	// SQLi only has its fields reordered, which doesn't count as a change
+	if len(diff.Changed) != 1 {
+		t.Fatalf("got changed %+v, want XSS only", diff.Changed)
+	}
+	change := diff.Changed[0]
+	if change.Before.Id != 1 || change.After.Id != 11 || !reflect.DeepEqual(change.Fields, []string{"Title", "Severity"}) {
+		t.Errorf("got change from %v to %v in fields %v", change.Before.Id, change.After.Id, change.Fields)
+	}
+}
+
+func TestSetIssueSeverities(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /issues": `[
+			{"id": 1, "title": "XSS", "fields": {"Title": "XSS", "Severity": "Medium", "Description": "Reflected"}},
+			{"id": 2, "title": "SQLi", "fields": {"Title": "SQLi", "Severity": "High"}},
+			{"id": 3, "title": "CSRF", "fields": {"Title": "CSRF"}},
+			{"id": 4, "title": "Weak TLS", "fields": {"Title": "Weak TLS", "Severity": "Low"}}
+		]`,
+		"PUT /issues/1": `{"id": 1, "title": "XSS"}`,
+		"PUT /issues/3": `{"id": 3, "title": "CSRF"}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	unmatched, err := gd.SetIssueSeverities(&Project{Id: 1}, map[string]string{
+		"xss": "High",
+		"CSRF": "Low",
+		"SQLi": "Critical",
+		"RCE": "Critical",
+		"Open Redirect": "Low",
+	})
+	// There is no PUT route for issue 2, so the server answers 404
+	if err == nil || err.Error() != "could not set severity of 1 issues: issue 2: could not update issue" {
+		t.Errorf("got error %v", err)
+	}
+	if want := []string{"Open Redirect", "RCE"}; !reflect.DeepEqual(unmatched, want) {
+		t.Errorf("got unmatched %v, want %v", unmatched, want)
+	}
+	if want := []string{"PUT /issues/1", "PUT /issues/2", "PUT /issues/3"}; !reflect.DeepEqual(fake.sent(), want) {
+		t.Errorf("got requests %v, want %v", fake.sent(), want)
+	}
+	// The other fields are kept and a missing Severity field is added
+	want := `#[Title]#\r\nXSS\r\n\r\n#[Severity]#\r\nHigh\r\n\r\n#[Description]#\r\nReflected`
+	if body := fake.bodies["PUT /issues/1"]; !strings.Contains(body, want) {
+		t.Errorf("got body %s, want %s", body, want)
+	}
+	if body := fake.bodies["PUT /issues/3"]; !strings.Contains(body, `#[Title]#\r\nCSRF\r\n\r\n#[Severity]#\r\nLow`) {
+		t.Errorf("got body %s", body)
+	}
+}
@@ -1,21 +1,69 @@
 package godradis
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/iancoleman/orderedmap"
 	"github.com/pkg/errors"
 )
 
+// IssueLibState is the review state stored in IssueLibEntry.State. The values mirror the state enum used by Dradis.
+type IssueLibState int
+
+const (
+	IssueLibStateDraft IssueLibState = 0
+	IssueLibStateReadyForReview IssueLibState = 1
+	IssueLibStatePublished IssueLibState = 2
+)
+
+// String returns the name Dradis uses for the state, or the number for states it does not define.
+func (s IssueLibState) String() string {
+	switch s {
+	case IssueLibStateDraft:
+		return "draft"
+	case IssueLibStateReadyForReview:
+		return "ready_for_review"
+	case IssueLibStatePublished:
+		return "published"
+	}
+	return fmt.Sprintf("IssueLibState(%d)", int(s))
+}
+
 type IssueLibEntry struct {
 	Id int `json:"id"`
 	Title string `json:"title"`
 	Fields orderedmap.OrderedMap `json:"fields"`
-	State int `json:"state"`
+	State IssueLibState `json:"state"`
 	Content string `json:"content"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
 
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings, and the fields as either an object
+// or an array of key/value pairs.
+func (i *IssueLibEntry) UnmarshalJSON(data []byte) error {
+	type issueLibEntry IssueLibEntry
+	aux := struct {
+		*issueLibEntry
+		Id flexInt `json:"id"`
+		State flexInt `json:"state"`
+		Fields flexFields `json:"fields"`
+	}{issueLibEntry: (*issueLibEntry)(i), Id: flexInt(i.Id), State: flexInt(i.State), Fields: flexFields(i.Fields)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	i.Id = int(aux.Id)
+	i.State = IssueLibState(aux.State)
+	i.Fields = orderedmap.OrderedMap(aux.Fields)
+	return nil
+}
+
+// IsPublished reports whether the entry is in the published state.
+func (i *IssueLibEntry) IsPublished() bool {
+	return i.State == IssueLibStatePublished
+}
+
 func (i *IssueLibEntry) SetField(key, value string) {
 	i.Fields.Set(key, value)
 }
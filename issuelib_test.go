@@ -0,0 +1,157 @@
+package godradis
+
+import (
+	"fmt"
+	"github.com/iancoleman/orderedmap"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestIssueLibStates(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /addons/issuelib/entries": `[
+			{"id": 1, "title": "XSS", "state": 0},
+			{"id": 2, "title": "SQLi", "state": 1},
+			{"id": 3, "title": "CSRF", "state": "2"},
+			{"id": 4, "title": "RCE", "state": 7}
+		]`,
+	}))
+	entries, err := gd.GetIssueLibrary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		state IssueLibState
+		name string
+		published bool
+	}{
+		{IssueLibStateDraft, "draft", false},
+		{IssueLibStateReadyForReview, "ready_for_review", false},
+		{IssueLibStatePublished, "published", true},
+		{IssueLibState(7), "IssueLibState(7)", false},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %v entries, want %v", len(entries), len(want))
+	}
+	for i, w := range want {
+		entry := entries[i]
+		if entry.State != w.state || entry.State.String() != w.name || entry.IsPublished() != w.published {
+			t.Errorf("entry %v: got state %v, IsPublished %v", entry.Id, entry.State, entry.IsPublished())
+		}
+	}
+}
+
+func TestCreateIssueLibraryEntrySendsState(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"POST /addons/issuelib/entries": `{"id": 5, "title": "XSS", "state": 2}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	entry, err := gd.CreateIssueLibraryEntryFromText("#[Title]#\r\nXSS", IssueLibStatePublished)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := fake.bodies["POST /addons/issuelib/entries"]; !strings.Contains(body, `"state":2`) {
+		t.Errorf("got body %s, want the published state", body)
+	}
+	if !entry.IsPublished() {
+		t.Errorf("got state %v, want published", entry.State)
+	}
+}
+
+func TestCopyIssueLibraryEntry(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"POST /addons/issuelib/entries": `{"id": 6, "title": "Stored XSS"}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	source := IssueLibEntry{Id: 5, Title: "XSS", Fields: *orderedmap.New()}
+	source.SetField("Title", "XSS")
+	source.SetField("Severity", "High")
+	source.SetField("Description", "Reflected input")
+	entry, err := gd.CopyIssueLibraryEntry(&source, "Stored XSS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Id != 6 {
+		t.Errorf("got entry %v", entry.Id)
+	}
+	want := `{"entry":{"content":"#[Title]#\r\nStored XSS\r\n\r\n#[Severity]#\r\nHigh\r\n\r\n#[Description]#\r\nReflected input\r\n\r\n"}}`
+	if body := fake.bodies["POST /addons/issuelib/entries"]; body != want {
+		t.Errorf("got body %s, want %s", body, want)
+	}
+	if title, _ := source.GetField("Title"); title != "XSS" {
+		t.Errorf("the source title was changed to %q", title)
+	}
+}
+
+func TestCopyIssueLibraryTo(t *testing.T) {
+	src, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /addons/issuelib/entries": `[
+			{"id": 1, "title": "XSS", "state": 2, "content": "#[Title]#\r\nXSS\r\n\r\n#[Severity]#\r\nHigh"},
+			{"id": 2, "title": "csrf", "state": 2, "content": "#[Title]#\r\ncsrf"},
+			{"id": 3, "title": "SQLi", "state": 0, "content": "#[Title]#\r\nSQLi\r\n\r\n#[Severity]#\r\nCritical"},
+			{"id": 4, "title": "xss", "state": 1, "content": "#[Title]#\r\nxss"}
+		]`,
+	}))
+	var mu sync.Mutex
+	var bodies []string
+	dst, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" {
+			w.Write([]byte(`[{"id": 7, "title": "CSRF", "content": "#[Title]#\r\nCSRF"}]`))
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		id := 7 + len(bodies)
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id": %v}`, id)
+	}))
+
+	created, err := src.CopyIssueLibraryTo(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 2 || created[0].Id != 8 || created[1].Id != 9 {
+		t.Errorf("got created entries %+v, want 8 and 9", created)
+	}
+	want := []string{
+		`{"entry":{"content":"#[Title]#\r\nXSS\r\n\r\n#[Severity]#\r\nHigh","state":2}}`,
+		`{"entry":{"content":"#[Title]#\r\nSQLi\r\n\r\n#[Severity]#\r\nCritical","state":0}}`,
+	}
+	if !reflect.DeepEqual(bodies, want) {
+		t.Errorf("got bodies %v, want %v", bodies, want)
+	}
+}
+
+func TestGetProjectIssueLibrary(t *testing.T) {
+	var mu sync.Mutex
+	var headers []http.Header
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pro/api/addons/issuelib/entries" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		mu.Lock()
+		headers = append(headers, r.Header)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": 1, "title": "XSS"}]`))
+	}))
+	if entries, err := gd.GetIssueLibrary(); err != nil || len(entries) != 1 {
+		t.Fatalf("got entries %v and error %v", entries, err)
+	}
+	if entries, err := gd.GetProjectIssueLibrary(&Project{Id: 45}); err != nil || len(entries) != 1 {
+		t.Fatalf("got entries %v and error %v", entries, err)
+	}
+	if _, ok := headers[0]["Dradis-Project-Id"]; ok {
+		t.Errorf("the global library was requested with project id %q", headers[0].Get("Dradis-Project-Id"))
+	}
+	if got := headers[1].Get("Dradis-Project-Id"); got != "45" {
+		t.Errorf("got project id %q for the project library, want 45", got)
+	}
+}
@@ -0,0 +1,133 @@
+package godradis
+
+import (
+	"fmt"
+	"github.com/iancoleman/orderedmap"
+	"strings"
+)
+
+// RenderOption controls what RenderProjectMarkdown and RenderIssueEvidence include.
+type RenderOption func(*renderOptions)
+
+type renderOptions struct {
+	reportableOnly bool
+}
+
+// WithReportableOnly sets whether evidence for which Evidence.IsReportable is false is left out. Defaults to false.
+func WithReportableOnly(reportableOnly bool) RenderOption {
+	return func(o *renderOptions) {
+		o.reportableOnly = reportableOnly
+	}
+}
+
+func newRenderOptions(opts []RenderOption) renderOptions {
+	options := renderOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// includes reports whether evidence is rendered under these options
+func (o renderOptions) includes(evidence *Evidence) bool {
+	return !o.reportableOnly || evidence.IsReportable()
+}
+
+/*
+RenderProjectMarkdown builds a Markdown report for a project. Each issue gets a section containing its fields, followed by
+the evidence attached to it grouped by node label. The issue's Title field is used as the section heading rather than
+being repeated as a field. Pass WithReportableOnly(true) to leave out evidence that isn't reportable.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    report, _ := gd.RenderProjectMarkdown(&project)
+    ioutil.WriteFile("report.md", []byte(report), 0644)
+ */
+func (gd *Godradis) RenderProjectMarkdown(project *Project, opts ...RenderOption) (string, error) {
+	options := newRenderOptions(opts)
+	issues, err := gd.GetAllIssues(project)
+	if err != nil {
+		return "", err
+	}
+	nodes, err := gd.GetAllNodes(project, WithNotes(false))
+	if err != nil {
+		return "", err
+	}
+
+	var md strings.Builder
+	fmt.Fprintf(&md, "# %s\n\n", project.Name)
+	for _, issue := range issues {
+		fmt.Fprintf(&md, "## %s\n\n", issue.Title)
+		writeMarkdownFields(&md, &issue.Fields, "###", "Title")
+
+		var evidenceSections strings.Builder
+		for i := range nodes {
+			for j := range nodes[i].Evidence {
+				evidence := &nodes[i].Evidence[j]
+				if evidence.Issue.Id != issue.Id || !options.includes(evidence) {
+					continue
+				}
+				fmt.Fprintf(&evidenceSections, "#### %s\n\n", nodes[i].Label)
+				writeMarkdownFields(&evidenceSections, &evidence.Fields, "#####")
+			}
+		}
+		if evidenceSections.Len() > 0 {
+			md.WriteString("### Evidence\n\n")
+			md.WriteString(evidenceSections.String())
+		}
+	}
+	return md.String(), nil
+}
+
+/*
+RenderIssueEvidence collects the evidence for issue from every node in the project and joins it into a single document,
+for use as a report appendix. Each piece of evidence is rendered with PlainContent under a Markdown heading holding the
+label of its node. Nodes appear in the order the server lists them, and evidence on the same node in creation order.
+Pass WithReportableOnly(true) to leave out evidence that isn't reportable.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    issue, _ := gd.GetIssueByTitle(&project, "Cross-Site Scripting")
+    appendix, _ := gd.RenderIssueEvidence(&project, &issue)
+ */
+func (gd *Godradis) RenderIssueEvidence(project *Project, issue *Issue, opts ...RenderOption) (string, error) {
+	options := newRenderOptions(opts)
+	nodes, err := gd.GetAllNodes(project, WithNotes(false))
+	if err != nil {
+		return "", err
+	}
+	var sections []string
+	for i := range nodes {
+		for j := range nodes[i].Evidence {
+			evidence := &nodes[i].Evidence[j]
+			if evidence.Issue.Id != issue.Id || !options.includes(evidence) {
+				continue
+			}
+			sections = append(sections, fmt.Sprintf("## %s\n\n%s\n", nodes[i].Label, evidence.PlainContent()))
+		}
+	}
+	return strings.Join(sections, "\n"), nil
+}
+
+// writeMarkdownFields writes each field as a heading at the given level followed by its value, skipping any keys in skip
+func writeMarkdownFields(md *strings.Builder, fields *orderedmap.OrderedMap, heading string, skip ...string) {
+	for _, k := range fields.Keys() {
+		skipped := false
+		for _, s := range skip {
+			if k == s {
+				skipped = true
+			}
+		}
+		if skipped {
+			continue
+		}
+		v, _ := fields.Get(k)
+		fmt.Fprintf(md, "%s %s\n\n%v\n\n", heading, k, strings.TrimSpace(fmt.Sprintf("%v", v)))
+	}
+}
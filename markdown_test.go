@@ -0,0 +1,120 @@
+package godradis
+
+import (
+	"github.com/iancoleman/orderedmap"
+	"strings"
+	"testing"
+)
+
+func TestRenderProjectMarkdown(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /issues": `[
+			{"id": 1, "title": "XSS", "fields": {"Title": "XSS", "Severity": "High"}},
+			{"id": 2, "title": "Weak TLS", "fields": {"Title": "Weak TLS", "Severity": "Low"}}
+		]`,
+		"GET /nodes": `[
+			{"id": 3, "label": "10.0.0.1", "evidence": [
+				{"id": 5, "issue": {"id": 1}, "fields": {"Port": "80/tcp", "Output": "<script>"}},
+				{"id": 6, "issue": {"id": 2}, "fields": {"Port": "443/tcp"}}
+			]},
+			{"id": 4, "label": "10.0.0.2", "evidence": [{"id": 7, "issue": {"id": 1}, "fields": {"Port": "8080/tcp", "Reportable": "no"}}]}
+		]`,
+	}))
+	report, err := gd.RenderProjectMarkdown(&Project{Id: 1, Name: "Foobar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Foobar\n\n" +
+		"## XSS\n\n### Severity\n\nHigh\n\n" +
+		"### Evidence\n\n" +
+		"#### 10.0.0.1\n\n##### Port\n\n80/tcp\n\n##### Output\n\n<script>\n\n" +
+		"#### 10.0.0.2\n\n##### Port\n\n8080/tcp\n\n##### Reportable\n\nno\n\n" +
+		"## Weak TLS\n\n### Severity\n\nLow\n\n" +
+		"### Evidence\n\n" +
+		"#### 10.0.0.1\n\n##### Port\n\n443/tcp\n\n"
+	if report != want {
+		t.Errorf("got\n%s\nwant\n%s", report, want)
+	}
+
+	report, err = gd.RenderProjectMarkdown(&Project{Id: 1, Name: "Foobar"}, WithReportableOnly(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(report, "10.0.0.2") || !strings.Contains(report, "80/tcp") {
+		t.Errorf("got\n%s\nwant the unreportable evidence left out", report)
+	}
+}
+
+// Evidence for issue 1 on two of three nodes, with a second issue's evidence in between
+const testRenderEvidence = `[
+	{"id": 3, "label": "10.0.0.2", "evidence": [
+		{"id": 5, "issue": {"id": 1}, "fields": {"Port": "80/tcp", "Output": "  <script>  "}},
+		{"id": 6, "issue": {"id": 2}, "fields": {"Port": "443/tcp"}},
+		{"id": 7, "issue": {"id": 1}, "fields": {"Port": "8080/tcp", "Reportable": " False "}}
+	]},
+	{"id": 4, "label": "10.0.0.3", "evidence": [{"id": 8, "issue": {"id": 2}, "fields": {"Port": "22/tcp"}}]},
+	{"id": 2, "label": "10.0.0.1", "evidence": [
+		{"id": 9, "issue": {"id": 1}, "fields": {"Port": "8443/tcp", "Reportable": "Yes"}}
+	]}
+]`
+
+func TestRenderIssueEvidence(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{"GET /nodes": testRenderEvidence}))
+	project := Project{Id: 1}
+	appendix, err := gd.RenderIssueEvidence(&project, &Issue{Id: 1, Project: &project})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nodes in server order and evidence in creation order, so 10.0.0.2 comes before 10.0.0.1
+	want := "## 10.0.0.2\n\n80/tcp\n\n<script>\n\n" +
+		"## 10.0.0.2\n\n8080/tcp\n\nFalse\n\n" +
+		"## 10.0.0.1\n\n8443/tcp\n\nYes\n"
+	if appendix != want {
+		t.Errorf("got\n%q\nwant\n%q", appendix, want)
+	}
+
+	appendix, err = gd.RenderIssueEvidence(&project, &Issue{Id: 3, Project: &project})
+	if err != nil || appendix != "" {
+		t.Errorf("got %q and error %v for an issue without evidence", appendix, err)
+	}
+}
+
+func TestRenderIssueEvidenceReportableOnly(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{"GET /nodes": testRenderEvidence}))
+	project := Project{Id: 1}
+	appendix, err := gd.RenderIssueEvidence(&project, &Issue{Id: 1, Project: &project}, WithReportableOnly(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Evidence 7 is marked " False " and left out; evidence without the field or marked "Yes" is kept
+	want := "## 10.0.0.2\n\n80/tcp\n\n<script>\n\n" +
+		"## 10.0.0.1\n\n8443/tcp\n\nYes\n"
+	if appendix != want {
+		t.Errorf("got\n%q\nwant\n%q", appendix, want)
+	}
+}
+
+func TestEvidenceIsReportable(t *testing.T) {
+	tests := []struct {
+		value string
+		want bool
+	}{
+		{"True", true},
+		{"yes", true},
+		{"1", true},
+		{"", true},
+		{"false", false},
+		{" NO ", false},
+		{"0", false},
+	}
+	for _, tt := range tests {
+		evidence := Evidence{Fields: *orderedmap.New()}
+		evidence.Fields.Set("Reportable", tt.value)
+		if got := evidence.IsReportable(); got != tt.want {
+			t.Errorf("Reportable %q: got %v, want %v", tt.value, got, tt.want)
+		}
+	}
+	if evidence := (Evidence{Fields: *orderedmap.New()}); !evidence.IsReportable() {
+		t.Error("evidence without a Reportable field is not reportable")
+	}
+}
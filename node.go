@@ -1,6 +1,7 @@
 package godradis
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
 	"github.com/ryanuber/go-glob"
@@ -8,11 +9,56 @@ import (
 	"sync"
 )
 
+// NodeType is the kind of node stored in Node.TypeId. CreateNode and GetOrCreateNode take a NodeType so that a type ID
+// cannot be confused with the parentId and position ints that follow it.
+type NodeType int
+
+const (
+	NodeTypeDefault NodeType = 0
+	NodeTypeHost NodeType = 1
+)
+
+func validateNodeType(typeId NodeType) error {
+	if typeId != NodeTypeDefault && typeId != NodeTypeHost {
+		return errors.New(fmt.Sprintf("invalid node type %v: must be NodeTypeDefault (0) or NodeTypeHost (1)", typeId))
+	}
+	return nil
+}
+
+// NodeOption controls what GetAllNodes populates on the returned nodes.
+type NodeOption func(*nodeOptions)
+
+type nodeOptions struct {
+	evidence bool
+	notes bool
+}
+
+// WithEvidence sets whether GetAllNodes populates Node.Evidence and wires its back-references. Defaults to true.
+func WithEvidence(include bool) NodeOption {
+	return func(o *nodeOptions) {
+		o.evidence = include
+	}
+}
+
+// WithNotes sets whether GetAllNodes populates Node.Notes and wires its back-references. Defaults to true.
+func WithNotes(include bool) NodeOption {
+	return func(o *nodeOptions) {
+		o.notes = include
+	}
+}
+
+// NodeSpec describes a node to create with CreateNodeTree, along with the nodes to create beneath it.
+type NodeSpec struct {
+	Label string
+	TypeId NodeType
+	Children []NodeSpec
+}
+
 type Node struct {
 	Mu sync.Mutex
 	Id int `json:"id"`
 	Label string `json:"label"`
-	TypeId int `json:"type_id"`
+	TypeId NodeType `json:"type_id"`
 	ParentId int `json:"parent_id"`
 	Position int `json:"position"`
 	CreatedAt string `json:"created_at"`
@@ -22,6 +68,37 @@ type Node struct {
 	Project *Project
 }
 
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings.
+func (n *Node) UnmarshalJSON(data []byte) error {
+	type node Node
+	aux := struct {
+		*node
+		Id flexInt `json:"id"`
+		TypeId flexInt `json:"type_id"`
+		ParentId flexInt `json:"parent_id"`
+		Position flexInt `json:"position"`
+	}{node: (*node)(n), Id: flexInt(n.Id), TypeId: flexInt(n.TypeId), ParentId: flexInt(n.ParentId), Position: flexInt(n.Position)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	n.Id = int(aux.Id)
+	n.TypeId = NodeType(aux.TypeId)
+	n.ParentId = int(aux.ParentId)
+	n.Position = int(aux.Position)
+	return nil
+}
+
+// Type returns the node's TypeId as a NodeType.
+func (n *Node) Type() NodeType {
+	return n.TypeId
+}
+
+// IsHost reports whether the node is a host node.
+func (n *Node) IsHost() bool {
+	return n.TypeId == NodeTypeHost
+}
+
 func (n *Node) GetEvidenceById(id int) (*Evidence, error) {
 	for i, evidence := range n.Evidence {
 		if evidence.Id == id {
@@ -55,6 +132,16 @@ func (n *Node) GetEvidenceByField(key, value string) []*Evidence {
 	return evidenceInstances
 }
 
+func (n *Node) GetEvidenceByContent(substr string) []*Evidence {
+	var evidenceInstances []*Evidence
+	for i, evidence := range n.Evidence {
+		if strings.Contains(strings.ToLower(evidence.Content), strings.ToLower(substr)) {
+			evidenceInstances = append(evidenceInstances, &n.Evidence[i])
+		}
+	}
+	return evidenceInstances
+}
+
 func (n *Node) GetNoteById(id int) (*Note, error) {
 	for i, note := range n.Notes {
 		if note.Id == id {
@@ -74,6 +161,14 @@ func (n *Node) GetNotesByTitle(title string) []*Note {
 	return notes
 }
 
+// projectId returns the ID of the node's project, or an error if the node was built without a Project reference
+func (n *Node) projectId() (int, error) {
+	if n.Project == nil {
+		return 0, errors.New(fmt.Sprintf("node %v has no Project reference", n.Id))
+	}
+	return n.Project.Id, nil
+}
+
 func (n *Node) setEvidenceNodeReferences() {
 	for i := range n.Evidence {
 		n.setEvidenceNodeReference(&n.Evidence[i])
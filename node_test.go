@@ -0,0 +1,502 @@
+package godradis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"github.com/pkg/errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Node 1 is the primary, node 2 a duplicate with a child (3) and a grandchild (4), and node 5 is unrelated
+const testNodeTree = `[
+	{"id": 1, "label": "10.0.0.1", "parent_id": null},
+	{"id": 2, "label": "10.0.0.1", "parent_id": null},
+	{"id": 3, "label": "80/tcp", "parent_id": 2},
+	{"id": 4, "label": "http", "parent_id": 3},
+	{"id": 5, "label": "10.0.0.2", "parent_id": null}
+]`
+
+func TestDeleteNodeRecursive(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /nodes": testNodeTree,
+		"DELETE /nodes/2": `{"message": "Resource deleted successfully"}`,
+		"DELETE /nodes/3": `{"message": "Resource deleted successfully"}`,
+		"DELETE /nodes/4": `{"message": "Resource deleted successfully"}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	if err := gd.DeleteNodeRecursive(&Node{Id: 2, Project: &project}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"DELETE /nodes/4", "DELETE /nodes/3", "DELETE /nodes/2"}
+	if got := fake.sent(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got requests %v, want %v", got, want)
+	}
+}
+
+func TestDeleteNodeRecursiveRejectsUnknownNodes(t *testing.T) {
+	fake := newFakeDradis(map[string]string{"GET /nodes": testNodeTree})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	for _, id := range []int{0, 99} {
+		if err := gd.DeleteNodeRecursive(&Node{Id: id, Project: &project}); err == nil {
+			t.Errorf("expected an error deleting node %v", id)
+		}
+	}
+	if sent := fake.sent(); len(sent) != 0 {
+		t.Errorf("unexpected requests %v", sent)
+	}
+}
+
+func TestMergeNodes(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /nodes": testNodeTree,
+		"GET /nodes/2/attachments": `[]`,
+		"GET /nodes/2/evidence": `[{"id": 7, "content": "#[Port]#\n80", "issue": {"id": 3, "title": "XSS"}}]`,
+		"GET /nodes/2/notes": `[{"id": 8, "category_id": 1, "text": "#[Title]#\nSeen"}]`,
+		"POST /nodes/1/evidence": `{"id": 9, "content": "#[Port]#\n80", "issue": {"id": 3, "title": "XSS"}}`,
+		"POST /nodes/1/notes": `{"id": 10, "category_id": 1, "text": "#[Title]#\nSeen"}`,
+		"PUT /nodes/3": `{"id": 3, "label": "80/tcp", "parent_id": 1}`,
+		"DELETE /nodes/2": `{"message": "Resource deleted successfully"}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	primary := Node{Id: 1, Project: &project}
+	if err := gd.MergeNodes(&primary, []*Node{{Id: 2, Project: &project}}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"POST /nodes/1/evidence", "POST /nodes/1/notes", "PUT /nodes/3", "DELETE /nodes/2"}
+	if got := fake.sent(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got requests %v, want %v", got, want)
+	}
+	if body := fake.bodies["PUT /nodes/3"]; body != `{"node":{"parent_id":1}}` {
+		t.Errorf("child moved with %s", body)
+	}
+	if body := fake.bodies["POST /nodes/1/evidence"]; !strings.Contains(body, `"issue_id":"3"`) {
+		t.Errorf("evidence copied with %s", body)
+	}
+}
+
+func TestMergeNodesKeepsDupesWithAttachments(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /nodes": testNodeTree,
+		"GET /nodes/5/attachments": `[{"filename": "shot.png", "link": "/pro/projects/1/nodes/5/attachments/shot.png"}]`,
+	})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	err := gd.MergeNodes(&Node{Id: 1, Project: &project}, []*Node{{Id: 5, Project: &project}})
+	if err == nil || !strings.Contains(err.Error(), "attachments") {
+		t.Errorf("got error %v", err)
+	}
+	if sent := fake.sent(); len(sent) != 0 {
+		t.Errorf("unexpected requests %v", sent)
+	}
+}
+
+func TestMergeNodesRejectsInvalidDupes(t *testing.T) {
+	fake := newFakeDradis(map[string]string{"GET /nodes": testNodeTree})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	tests := []struct {
+		name string
+		primary *Node
+		dupe *Node
+	}{
+		{"itself", &Node{Id: 1, Project: &project}, &Node{Id: 1, Project: &project}},
+		{"other project", &Node{Id: 1, Project: &project}, &Node{Id: 2, Project: &Project{Id: 2}}},
+		{"ancestor", &Node{Id: 4, Project: &project}, &Node{Id: 2, Project: &project}},
+	}
+	for _, tt := range tests {
+		if err := gd.MergeNodes(tt.primary, []*Node{tt.dupe}); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+	if sent := fake.sent(); len(sent) != 0 {
+		t.Errorf("unexpected requests %v", sent)
+	}
+}
+
+func TestNodesRequireProject(t *testing.T) {
+	fake := newFakeDradis(map[string]string{})
+	gd, _ := newTestClient(t, fake)
+	if err := gd.DeleteNodeRecursive(&Node{Id: 2}); err == nil {
+		t.Error("expected an error for a node without a project")
+	}
+	err := gd.UpdateNode(&Node{Id: 2}, "localhost", nil, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "node 2 has no Project reference") {
+		t.Errorf("got error %v updating a node without a project", err)
+	}
+	node := Node{Id: 2, Project: &Project{Id: 1}}
+	issue := Issue{Id: 3, Project: &Project{Id: 4}}
+	_, err = gd.CreateEvidenceFromText(&node, &issue, "#[Port]#\r\n80")
+	if err == nil || !strings.Contains(err.Error(), "node 2 belongs to project 1 but issue 3 belongs to project 4") {
+		t.Errorf("got error %v creating evidence across projects", err)
+	}
+	if sent := fake.sent(); len(sent) != 0 {
+		t.Errorf("unexpected requests %v", sent)
+	}
+}
+
+func TestReparentNodes(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, r.URL.Path+" "+string(body))
+		mu.Unlock()
+		// Echo the requested parent back, as Dradis does
+		var req struct {
+			Node struct {
+				ParentId *int `json:"parent_id"`
+			} `json:"node"`
+		}
+		json.Unmarshal(body, &req)
+		parentId := "null"
+		if req.Node.ParentId != nil {
+			parentId = fmt.Sprint(*req.Node.ParentId)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": %s, "parent_id": %s}`, strings.TrimPrefix(r.URL.Path, "/pro/api/nodes/"), parentId)
+	}))
+	project := Project{Id: 1}
+	nodes := []*Node{{Id: 2, ParentId: 1, Project: &project}, {Id: 3, ParentId: 2, Project: &project}}
+	if err := gd.ReparentNodes(nodes, 5); err != nil {
+		t.Fatal(err)
+	}
+	if nodes[0].ParentId != 5 || nodes[1].ParentId != 5 {
+		t.Errorf("got parents %v and %v, want 5", nodes[0].ParentId, nodes[1].ParentId)
+	}
+	if err := gd.ReparentNodes(nodes, 0); err != nil {
+		t.Fatal(err)
+	}
+	if nodes[0].ParentId != 0 || nodes[1].ParentId != 0 {
+		t.Errorf("got parents %v and %v, want the root", nodes[0].ParentId, nodes[1].ParentId)
+	}
+	want := []string{
+		`/pro/api/nodes/2 {"node":{"parent_id":5}}`,
+		`/pro/api/nodes/3 {"node":{"parent_id":5}}`,
+		`/pro/api/nodes/2 {"node":{"parent_id":null}}`,
+		`/pro/api/nodes/3 {"node":{"parent_id":null}}`,
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(bodies, want) {
+		t.Errorf("got requests %v, want %v", bodies, want)
+	}
+}
+
+func TestReparentNodesAggregatesFailures(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{"PUT /nodes/3": `{"id": 3, "parent_id": 5}`}))
+	project := Project{Id: 1}
+	nodes := []*Node{{Id: 2, Project: &project}, {Id: 3, Project: &project}, {Id: 4, Project: &project}}
+	err := gd.ReparentNodes(nodes, 5)
+	if err == nil || !strings.Contains(err.Error(), "could not move 2 of 3 nodes") {
+		t.Errorf("got error %v", err)
+	}
+	if nodes[1].ParentId != 5 || nodes[0].ParentId != 0 {
+		t.Errorf("got parents %v and %v", nodes[0].ParentId, nodes[1].ParentId)
+	}
+}
+
+func TestReparentNodesContextCancelKeepsFailures(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Node 1 fails to move and the caller gives up while node 2 is being moved
+		if r.URL.Path == "/pro/api/nodes/2" {
+			cancel()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id": 2, "parent_id": 5}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	project := Project{Id: 1}
+	nodes := []*Node{{Id: 1, Project: &project}, {Id: 2, Project: &project}, {Id: 3, Project: &project}}
+	moved, err := gd.ReparentNodesContext(ctx, nodes, 5)
+	if moved != 1 {
+		t.Errorf("moved %v nodes, want 1", moved)
+	}
+	if errors.Cause(err) != context.Canceled || !strings.Contains(err.Error(), "node 1") {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestGetOrCreateNode(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /nodes": testNodeTree,
+		"POST /nodes": `{"id": 6, "label": "10.0.0.3", "type_id": 1}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	node, created, err := gd.GetOrCreateNode(&project, "10.0.0.2", NodeTypeHost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || node.Id != 5 || node.Project != &project {
+		t.Errorf("got node %v, created %v", node.Id, created)
+	}
+	node, created, err = gd.GetOrCreateNode(&project, "10.0.0.3", NodeTypeHost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created || node.Id != 6 {
+		t.Errorf("got node %v, created %v", node.Id, created)
+	}
+	if sent := fake.sent(); !reflect.DeepEqual(sent, []string{"POST /nodes"}) {
+		t.Errorf("got requests %v", sent)
+	}
+}
+
+func TestGetEmptyNodes(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /nodes": `[
+			{"id": 1, "label": "10.0.0.1", "evidence": [{"id": 3, "content": "#[Port]#\n80"}], "notes": []},
+			{"id": 2, "label": "10.0.0.2", "evidence": [], "notes": [{"id": 4, "text": "#[Title]#\nSeen"}]},
+			{"id": 3, "label": "10.0.0.3", "evidence": [], "notes": []}
+		]`,
+	}))
+	project := Project{Id: 1}
+	nodes, err := gd.GetEmptyNodes(&project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].Id != 3 || nodes[0].Project != &project {
+		t.Errorf("got %v empty nodes", len(nodes))
+	}
+}
+
+func TestCreateNodeTree(t *testing.T) {
+	var bodies []string
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		// The second node (22/tcp) fails, so its child is skipped but its sibling is still created
+		if len(bodies) == 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id": %v}`, len(bodies)*10)
+	}))
+	project := Project{Id: 1}
+	nodes, err := gd.CreateNodeTree(&project, []NodeSpec{
+		{Label: "10.0.0.1", TypeId: NodeTypeHost, Children: []NodeSpec{
+			{Label: "22/tcp", Children: []NodeSpec{{Label: "ssh"}}},
+			{Label: "443/tcp"},
+		}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "22/tcp") {
+		t.Errorf("got error %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].Id != 10 || nodes[1].Id != 30 {
+		t.Fatalf("got %v nodes", len(nodes))
+	}
+	if len(bodies) != 3 || !strings.Contains(bodies[2], `"parent_id":10`) || !strings.Contains(bodies[2], `"position":1`) {
+		t.Errorf("got requests %v", bodies)
+	}
+}
+
+func TestCloneNode(t *testing.T) {
+	var created []string
+	nextId := 100
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		body, _ := ioutil.ReadAll(r.Body)
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/pro/api/nodes":
+			w.Write([]byte(`[
+				{"id": 2, "label": "10.0.0.1", "type_id": 1, "parent_id": null,
+					"notes": [{"id": 8, "category_id": 1, "text": "#[Title]#\nSeen"}],
+					"evidence": [{"id": 7, "content": "#[Port]#\n80", "issue": {"id": 3, "title": "XSS"}}]},
+				{"id": 3, "label": "80/tcp", "parent_id": 2, "notes": [], "evidence": []}
+			]`))
+		case r.Method == "POST":
+			created = append(created, fmt.Sprintf("%s %s", r.URL.Path, body))
+			w.WriteHeader(http.StatusCreated)
+			nextId++
+			fmt.Fprintf(w, `{"id": %v}`, nextId)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	project := Project{Id: 1}
+	clone, err := gd.CloneNode(&Node{Id: 2, Project: &project}, "10.0.0.9", 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.Id != 101 {
+		t.Errorf("got clone %v", clone.Id)
+	}
+	want := []string{
+		`/pro/api/nodes {"node":{"label":"10.0.0.9","type_id":1}}`,
+		`/pro/api/nodes/101/notes {"note":{"text":"#[Title]#\nSeen","category_id":"1"}}`,
+		`/pro/api/nodes/101/evidence {"evidence":{"content":"#[Port]#\n80","issue_id":"3"}}`,
+		`/pro/api/nodes {"node":{"label":"80/tcp","parent_id":101}}`,
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("got requests\n%v\nwant\n%v", strings.Join(created, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestGetNodeByLabelCaseSensitivity(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /nodes": `[{"id": 1, "label": "web-01"}, {"id": 2, "label": "WEB-01"}]`,
+	}))
+	project := Project{Id: 1}
+	node, err := gd.GetNodeByLabel(&project, "Web-01")
+	if err != nil || node.Id != 1 {
+		t.Errorf("case-insensitive lookup got node %v, error %v", node.Id, err)
+	}
+	gd.Config.CaseSensitiveNames = true
+	node, err = gd.GetNodeByLabel(&project, "WEB-01")
+	if err != nil || node.Id != 2 {
+		t.Errorf("case-sensitive lookup got node %v, error %v", node.Id, err)
+	}
+	if _, err = gd.GetNodeByLabel(&project, "Web-01"); err == nil {
+		t.Error("case-sensitive lookup matched a label differing by case")
+	}
+}
+
+func TestCreateNodeTypes(t *testing.T) {
+	tests := []struct {
+		typeId NodeType
+		response string
+		wantBody string
+		wantHost bool
+	}{
+		{NodeTypeHost, `{"id": 6, "label": "10.0.0.3", "type_id": 1}`, `"type_id":1`, true},
+		{NodeTypeDefault, `{"id": 6, "label": "10.0.0.3", "type_id": 0}`, `{"node":{"label":"10.0.0.3"}}`, false},
+	}
+	for _, tt := range tests {
+		fake := newFakeDradis(map[string]string{"POST /nodes": tt.response})
+		gd, _ := newTestClient(t, fake)
+		node, err := gd.CreateNode(&Project{Id: 1}, "10.0.0.3", tt.typeId, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body := fake.bodies["POST /nodes"]; !strings.Contains(body, tt.wantBody) {
+			t.Errorf("type %v: got body %s, want it to contain %s", tt.typeId, body, tt.wantBody)
+		}
+		if node.Type() != tt.typeId || node.IsHost() != tt.wantHost {
+			t.Errorf("type %v: got node type %v, IsHost %v", tt.typeId, node.Type(), node.IsHost())
+		}
+	}
+}
+
+func TestNodeTypeRejectsInvalidValues(t *testing.T) {
+	fake := newFakeDradis(map[string]string{})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	if _, err := gd.CreateNode(&project, "10.0.0.3", NodeType(5), 0, 0); err == nil {
+		t.Error("expected an error creating a node with type 5")
+	}
+	for _, typeId := range []interface{}{NodeType(2), -1, "host"} {
+		if err := gd.UpdateNode(&Node{Id: 1, Project: &project}, nil, typeId, nil, nil); err == nil {
+			t.Errorf("expected an error updating a node to type %v", typeId)
+		}
+	}
+	if sent := fake.sent(); len(sent) != 0 {
+		t.Errorf("unexpected requests %v", sent)
+	}
+}
+
+const testNodesWithContent = `[
+	{"id": 1, "label": "10.0.0.1", "type_id": 1, "parent_id": null, "position": 2,
+		"evidence": [{"id": 3, "content": "#[Port]#\n80"}], "notes": [{"id": 4, "text": "#[Title]#\nSeen"}]},
+	{"id": 2, "label": "80/tcp", "parent_id": 1, "evidence": [], "notes": []}
+]`
+
+func TestGetAllNodesShallow(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{"GET /nodes": testNodesWithContent}))
+	project := Project{Id: 1}
+	full, err := gd.GetAllNodes(&project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shallow, err := gd.GetAllNodesShallow(&project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(full) != 2 || len(shallow) != 2 {
+		t.Fatalf("got %v full and %v shallow nodes", len(full), len(shallow))
+	}
+	if full[0].Evidence[0].Node != &full[0] || full[0].Notes[0].Node != &full[0] {
+		t.Error("GetAllNodes did not wire the evidence and note back-references")
+	}
+	for i := range shallow {
+		s, f := &shallow[i], &full[i]
+		if s.Id != f.Id || s.Label != f.Label || s.TypeId != f.TypeId || s.ParentId != f.ParentId || s.Position != f.Position {
+			t.Errorf("shallow node %v differs from %v", s.Id, f.Id)
+		}
+		if s.Evidence != nil || s.Notes != nil || s.Project != &project {
+			t.Errorf("shallow node %v has evidence %v, notes %v and project %p", s.Id, s.Evidence, s.Notes, s.Project)
+		}
+	}
+}
+
+func benchmarkGetAllNodes(b *testing.B, get func(*Godradis, *Project) ([]Node, error)) {
+	var nodes []string
+	for i := 1; i <= 500; i++ {
+		nodes = append(nodes, fmt.Sprintf(`{"id": %v, "label": "10.0.%v.%v", "evidence": [{"id": %v, "content": "#[Port]#\n80"}], "notes": [{"id": %v, "text": "#[Title]#\nSeen"}]}`, i, i/256, i%256, i, i))
+	}
+	gd, _ := newTestClient(b, newFakeDradis(map[string]string{"GET /nodes": "[" + strings.Join(nodes, ",") + "]"}))
+	project := Project{Id: 1}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := get(gd, &project); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetAllNodes(b *testing.B) {
+	benchmarkGetAllNodes(b, func(gd *Godradis, p *Project) ([]Node, error) { return gd.GetAllNodes(p) })
+}
+
+func BenchmarkGetAllNodesShallow(b *testing.B) {
+	benchmarkGetAllNodes(b, (*Godradis).GetAllNodesShallow)
+}
+
+func TestGetAllNodesOptions(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{"GET /nodes": testNodesWithContent}))
+	project := Project{Id: 1}
+	tests := []struct {
+		opts []NodeOption
+		evidence bool
+		notes bool
+	}{
+		{nil, true, true},
+		{[]NodeOption{WithEvidence(true), WithNotes(true)}, true, true},
+		{[]NodeOption{WithEvidence(false)}, false, true},
+		{[]NodeOption{WithNotes(false)}, true, false},
+		{[]NodeOption{WithEvidence(false), WithNotes(false)}, false, false},
+	}
+	for i, tt := range tests {
+		nodes, err := gd.GetAllNodes(&project, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		node := &nodes[0]
+		if node.Label != "10.0.0.1" || node.Project != &project {
+			t.Errorf("case %v: got node %v with project %p", i, node.Label, node.Project)
+		}
+		if (len(node.Evidence) == 1) != tt.evidence || (len(node.Notes) == 1) != tt.notes {
+			t.Errorf("case %v: got %v evidence and %v notes", i, len(node.Evidence), len(node.Notes))
+		}
+		if tt.evidence && node.Evidence[0].Node != node {
+			t.Errorf("case %v: evidence has no node reference", i)
+		}
+		if tt.notes && node.Notes[0].Node != node {
+			t.Errorf("case %v: note has no node reference", i)
+		}
+	}
+}
@@ -1,11 +1,38 @@
 package godradis
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/iancoleman/orderedmap"
 	"github.com/pkg/errors"
 )
 
+type NoteCategory struct {
+	Id int `json:"id"`
+	Name string `json:"name"`
+}
+
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings.
+func (n *NoteCategory) UnmarshalJSON(data []byte) error {
+	type noteCategory NoteCategory
+	aux := struct {
+		*noteCategory
+		Id flexInt `json:"id"`
+	}{noteCategory: (*noteCategory)(n), Id: flexInt(n.Id)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	n.Id = int(aux.Id)
+	return nil
+}
+
+// NoteInput is a single note to create with CreateNotes.
+type NoteInput struct {
+	Fields *orderedmap.OrderedMap
+	CategoryId int
+}
+
 type Note struct {
 	Id int `json:"id"`
 	CategoryId int `json:"category_id"`
@@ -15,6 +42,26 @@ type Note struct {
 	Node *Node
 }
 
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings, and the fields as either an object
+// or an array of key/value pairs.
+func (n *Note) UnmarshalJSON(data []byte) error {
+	type note Note
+	aux := struct {
+		*note
+		Id flexInt `json:"id"`
+		CategoryId flexInt `json:"category_id"`
+		Fields flexFields `json:"fields"`
+	}{note: (*note)(n), Id: flexInt(n.Id), CategoryId: flexInt(n.CategoryId), Fields: flexFields(n.Fields)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	n.Id = int(aux.Id)
+	n.CategoryId = int(aux.CategoryId)
+	n.Fields = orderedmap.OrderedMap(aux.Fields)
+	return nil
+}
+
 func (n *Note) SetField(key, value string) {
 	n.Fields.Set(key, value)
 }
@@ -27,6 +74,11 @@ func (n *Note) GetField(key string) (string, error) {
 	return value.(string), nil
 }
 
+// PlainContent returns the note's field values in order, separated by blank lines, without the "#[Key]#" markers.
+func (n *Note) PlainContent() string {
+	return plainContent(&n.Fields, n.Text)
+}
+
 func (n *Note) CopyFields() orderedmap.OrderedMap {
 	fields := orderedmap.New()
 	keys := n.Fields.Keys()
@@ -37,4 +89,13 @@ func (n *Note) CopyFields() orderedmap.OrderedMap {
 		}
 	}
 	return *fields
-}
\ No newline at end of file
+}
+
+// projectId returns the ID of the note's project, or an error if the note has no Node reference or its
+// Node has no Project reference
+func (n *Note) projectId() (int, error) {
+	if n.Node == nil {
+		return 0, errors.New(fmt.Sprintf("note %v has no Node reference", n.Id))
+	}
+	return n.Node.projectId()
+}
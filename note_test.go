@@ -0,0 +1,159 @@
+package godradis
+
+import (
+	"fmt"
+	"github.com/iancoleman/orderedmap"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGetAllNotesForProject(t *testing.T) {
+	const nodeCount = 50
+	routes := map[string]string{}
+	var nodes []string
+	for id := 1; id <= nodeCount; id++ {
+		nodes = append(nodes, fmt.Sprintf(`{"id": %v, "label": "10.0.0.%v"}`, id, id))
+		routes[fmt.Sprintf("GET /nodes/%v/notes", id)] = fmt.Sprintf(
+			`[{"id": %v, "text": "#[Title]#\nFirst"}, {"id": %v, "text": "#[Title]#\nSecond"}]`, id*10, id*10+1)
+	}
+	routes["GET /nodes"] = "[" + strings.Join(nodes, ",") + "]"
+	gd, _ := newTestClient(t, newFakeDradis(routes))
+	project := Project{Id: 1}
+	notes, err := gd.GetAllNotesForProject(&project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 2*nodeCount {
+		t.Fatalf("got %v notes, want %v", len(notes), 2*nodeCount)
+	}
+	for i, note := range notes {
+		nodeId := i/2 + 1
+		if note.Node == nil || note.Node.Id != nodeId || note.Id != nodeId*10+i%2 {
+			t.Fatalf("note %v: got id %v on node %+v", i, note.Id, note.Node)
+		}
+		if note.Node.Project != &project || len(note.Node.Notes) != 2 {
+			t.Errorf("note %v: node not linked to the project and its notes", i)
+		}
+	}
+}
+
+func TestGetAllNotesForProjectPartialFailure(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /nodes": `[{"id": 1}, {"id": 2}]`,
+		"GET /nodes/1/notes": `[{"id": 10, "text": "#[Title]#\nFirst"}]`,
+	}))
+	notes, err := gd.GetAllNotesForProject(&Project{Id: 1})
+	if err == nil || !strings.Contains(err.Error(), "node 2") {
+		t.Errorf("got error %v", err)
+	}
+	if len(notes) != 1 || notes[0].Id != 10 {
+		t.Errorf("got %+v", notes)
+	}
+}
+
+func TestCreateNoteWithCategory(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /categories": `[{"id": 1, "name": "Default category"}, {"id": 6, "name": "Hostnames"}]`,
+		"POST /nodes/2/notes": `{"id": 9, "category_id": 6, "text": "#[Hostnames]#\r\nfoo.com"}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	node := Node{Id: 2, Project: &Project{Id: 1}}
+	fields := orderedmap.New()
+	fields.Set("Hostnames", "foo.com")
+
+	note, err := gd.CreateNoteWithCategory(&node, fields, "hostnames")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Id != 9 || note.CategoryId != 6 {
+		t.Errorf("got note %v in category %v", note.Id, note.CategoryId)
+	}
+	if body := fake.bodies["POST /nodes/2/notes"]; !strings.Contains(body, `"category_id":"6"`) {
+		t.Errorf("got body %s, want category 6", body)
+	}
+
+	_, err = gd.CreateNoteWithCategory(&node, fields, "Open Ports")
+	if err == nil || !strings.Contains(err.Error(), "could not find note category Open Ports (available: Default category, Hostnames)") {
+		t.Errorf("got error %v for an unknown category", err)
+	}
+	if sent := fake.sent(); len(sent) != 1 {
+		t.Errorf("got requests %v, want only the first note created", sent)
+	}
+}
+
+func TestCreateNotes(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/pro/api/nodes/2/notes" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		id := len(bodies)
+		mu.Unlock()
+		if strings.Contains(string(body), `"category_id":"8"`) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id": %v, "text": "#[Title]#\r\nNote %v"}`, id, id)
+	}))
+	node := Node{Id: 2, Project: &Project{Id: 1}}
+	fields := orderedmap.New()
+	fields.Set("Title", "Note")
+
+	notes, err := gd.CreateNotes(&node, []NoteInput{
+		{Fields: fields, CategoryId: 6},
+		{Fields: fields, CategoryId: 8},
+		{Fields: fields, CategoryId: 7},
+	})
+	if err == nil || err.Error() != "could not create 1 of 3 notes: note 1: could not create note" {
+		t.Errorf("got error %v", err)
+	}
+	if len(notes) != 2 || notes[0].Id != 1 || notes[1].Id != 3 {
+		t.Fatalf("got notes %+v, want notes 1 and 3", notes)
+	}
+	if len(node.Notes) != 2 || node.Notes[0].Id != 1 || node.Notes[1].Id != 3 {
+		t.Errorf("got node notes %+v, want notes 1 and 3", node.Notes)
+	}
+	if notes[0].Node != &node {
+		t.Errorf("got node %p, want %p", notes[0].Node, &node)
+	}
+	for i, want := range []string{`"category_id":"6"`, `"category_id":"8"`, `"category_id":"7"`} {
+		if !strings.Contains(bodies[i], want) {
+			t.Errorf("got body %s, want %s", bodies[i], want)
+		}
+	}
+}
+
+func TestResolveNoteCategory(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /categories": `[{"id": 1, "name": "Default category"}, {"id": 6, "name": "Hostnames"}]`,
+	})
+	gd, _ := newTestClient(t, fake)
+	tests := []struct {
+		categoryId int
+		want string
+		wantErr string
+	}{
+		{6, "Hostnames", ""},
+		{1, "Default category", ""},
+		{9, "", "could not find note category with id 9"},
+	}
+	for _, tt := range tests {
+		name, err := gd.ResolveNoteCategory(&Note{Id: 3, CategoryId: tt.categoryId})
+		if name != tt.want || (err == nil) != (tt.wantErr == "") || (err != nil && err.Error() != tt.wantErr) {
+			t.Errorf("category %v: got %q and error %v, want %q and %q", tt.categoryId, name, err, tt.want, tt.wantErr)
+		}
+	}
+	// The categories are cached after the first lookup
+	if len(fake.requests) != 1 {
+		t.Errorf("got requests %v, want a single categories lookup", fake.requests)
+	}
+}
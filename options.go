@@ -0,0 +1,97 @@
+package godradis
+
+import (
+	"crypto/tls"
+	"fmt"
+	"github.com/pkg/errors"
+	"net/url"
+	"time"
+)
+
+// Option sets a configuration value for ConfigureWithOptions.
+type Option func(*Config) error
+
+// WithVerify sets whether TLS certificates on the Dradis server are checked.
+func WithVerify(verify bool) Option {
+	return func(c *Config) error {
+		c.Verify = verify
+		return nil
+	}
+}
+
+// WithTimeout sets the time limit for each request, including reading the response body.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) error {
+		if timeout < 0 {
+			return errors.New("timeout must not be negative")
+		}
+		c.Timeout = timeout
+		return nil
+	}
+}
+
+// WithProxy sends all requests through the HTTP proxy at proxyUrl.
+func WithProxy(proxyUrl string) Option {
+	return func(c *Config) error {
+		_, err := url.Parse(proxyUrl)
+		if err != nil {
+			return errors.Wrap(err, "invalid proxy url")
+		}
+		c.Proxy = proxyUrl
+		return nil
+	}
+}
+
+// WithRetry retries requests up to maxRetries times after a transport error or a 429, 502, 503 or 504 response. POST
+// requests, which aren't idempotent, are only retried after a 429 or 503.
+func WithRetry(maxRetries int) Option {
+	return func(c *Config) error {
+		if maxRetries < 0 {
+			return errors.New("maxRetries must not be negative")
+		}
+		c.MaxRetries = maxRetries
+		return nil
+	}
+}
+
+// WithRateLimit limits the client to requestsPerSecond requests per second. The limit is shared by every request made
+// through the client, including retries and the requests sent concurrently by helpers such as GetAllNotesForProject.
+func WithRateLimit(requestsPerSecond float64) Option {
+	return func(c *Config) error {
+		if requestsPerSecond < 0 {
+			return errors.New("requestsPerSecond must not be negative")
+		}
+		c.RateLimit = requestsPerSecond
+		return nil
+	}
+}
+
+// WithFieldFormat sets the layout of the body text built from an OrderedMap of fields.
+func WithFieldFormat(format FieldFormat) Option {
+	return func(c *Config) error {
+		if format.KeyPrefix == "" || format.KeySuffix == "" {
+			return errors.New("field format must have a key prefix and suffix")
+		}
+		c.FieldFormat = &format
+		return nil
+	}
+}
+
+// WithMinTLSVersion sets the lowest TLS version accepted from the Dradis server, e.g. tls.VersionTLS12.
+func WithMinTLSVersion(version uint16) Option {
+	return func(c *Config) error {
+		if version < tls.VersionTLS10 || version > tls.VersionTLS13 {
+			return errors.New(fmt.Sprintf("unsupported TLS version %#x", version))
+		}
+		c.MinTLSVersion = version
+		return nil
+	}
+}
+
+// WithCipherSuites restricts the cipher suites offered for TLS 1.2 and earlier. TLS 1.3 suites are not configurable.
+func WithCipherSuites(suites ...uint16) Option {
+	return func(c *Config) error {
+		c.CipherSuites = suites
+		return nil
+	}
+}
@@ -0,0 +1,239 @@
+package godradis
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConfigureWithOptions(t *testing.T) {
+	gd := Godradis{}
+	err := gd.ConfigureWithOptions("https://example.com", "abc", WithTimeout(30*time.Second),
+		WithProxy("http://127.0.0.1:8080"), WithRetry(3), WithVerify(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gd.Config.Timeout != 30*time.Second || gd.Config.Proxy != "http://127.0.0.1:8080" || gd.Config.MaxRetries != 3 ||
+		gd.Config.Verify || gd.Config.ApiKey != "abc" {
+		t.Errorf("got %+v", gd.Config)
+	}
+	if gd.httpClient.Timeout != 30*time.Second {
+		t.Errorf("client timeout %v", gd.httpClient.Timeout)
+	}
+
+	gd = Godradis{}
+	if err = gd.ConfigureWithOptions("https://example.com", "abc"); err != nil {
+		t.Fatal(err)
+	}
+	if !gd.Config.Verify {
+		t.Errorf("certificates not verified by default")
+	}
+
+	for _, opt := range []Option{WithTimeout(-1), WithRetry(-1), WithProxy("http://[::1"), WithMinTLSVersion(1)} {
+		if err = (&Godradis{}).ConfigureWithOptions("https://example.com", "abc", opt); err == nil {
+			t.Errorf("expected an error for an invalid option")
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	server := httptest.NewServer(newFakeDradis(map[string]string{"GET /teams": `[{"id": 1, "name": "Red"}]`}))
+	defer server.Close()
+	config := fmt.Sprintf(`{"dradis_url": %q, "api_key": "abc", "verify": true, "timeout": 5000000000}`, server.URL)
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	loaders := map[string]func(*Godradis) error{
+		"LoadConfig": func(gd *Godradis) error { return gd.LoadConfig(path) },
+		"LoadConfigFromReader": func(gd *Godradis) error { return gd.LoadConfigFromReader(bytes.NewBufferString(config)) },
+		"LoadConfigFromBytes": func(gd *Godradis) error { return gd.LoadConfigFromBytes([]byte(config)) },
+	}
+	for name, load := range loaders {
+		gd := Godradis{}
+		if err := load(&gd); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if gd.Config.BaseUrl != server.URL || gd.Config.ApiKey != "abc" || gd.httpClient.Timeout != 5*time.Second {
+			t.Errorf("%s: got config %+v and client timeout %v", name, gd.Config, gd.httpClient.Timeout)
+		}
+		// The client has to be usable straight away
+		if teams, err := gd.GetAllTeams(); err != nil || len(teams) != 1 {
+			t.Errorf("%s: got teams %v and error %v", name, teams, err)
+		}
+	}
+	if err := (&Godradis{}).LoadConfigFromReader(strings.NewReader(`{"dradis_url": `)); err == nil {
+		t.Error("expected an error for malformed JSON")
+	}
+}
+
+func TestConnectionPoolConfig(t *testing.T) {
+	gd := Godradis{}
+	err := gd.LoadConfigFromBytes([]byte(`{"dradis_url": "https://example.com", "api_key": "abc", "max_idle_conns": 50,
+		"max_idle_conns_per_host": 20, "max_conns_per_host": 30}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := gd.httpClient.Transport.(*http.Transport)
+	if tr.MaxIdleConns != 50 || tr.MaxIdleConnsPerHost != 20 || tr.MaxConnsPerHost != 30 {
+		t.Errorf("got MaxIdleConns %v, MaxIdleConnsPerHost %v, MaxConnsPerHost %v", tr.MaxIdleConns, tr.MaxIdleConnsPerHost,
+			tr.MaxConnsPerHost)
+	}
+
+	gd = Godradis{}
+	gd.Configure("https://example.com", "abc", true)
+	tr = gd.httpClient.Transport.(*http.Transport)
+	if tr.MaxIdleConns != 0 || tr.MaxIdleConnsPerHost != 0 || tr.MaxConnsPerHost != 0 {
+		t.Errorf("got non-default pool settings %v, %v, %v", tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		method string
+		status int
+		wantRequests int
+	}{
+		{"GET", http.StatusBadGateway, 2},
+		{"PUT", http.StatusServiceUnavailable, 2},
+		{"POST", http.StatusServiceUnavailable, 2},
+		{"POST", http.StatusTooManyRequests, 2},
+		{"POST", http.StatusBadGateway, 1},
+		{"POST", http.StatusGatewayTimeout, 1},
+		{"GET", http.StatusNotFound, 1},
+	}
+	for _, tt := range tests {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				w.WriteHeader(tt.status)
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+		gd := Godradis{}
+		if err := gd.ConfigureWithOptions(server.URL, "abc", WithRetry(1)); err != nil {
+			t.Fatal(err)
+		}
+		_, _, err := gd.Raw(tt.method, "teams", nil, []byte(`{}`))
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if requests != tt.wantRequests {
+			t.Errorf("%s after %v: got %v requests, want %v", tt.method, tt.status, requests, tt.wantRequests)
+		}
+	}
+}
+
+func TestRetryResendsBody(t *testing.T) {
+	for _, method := range []string{"POST", "PUT"} {
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if len(bodies) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+		gd := Godradis{}
+		if err := gd.ConfigureWithOptions(server.URL, "abc", WithRetry(1)); err != nil {
+			t.Fatal(err)
+		}
+		sent := `{"node":{"label":"10.0.0.1"}}`
+		_, _, err := gd.Raw(method, "nodes", nil, []byte(sent))
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(bodies, []string{sent, sent}) {
+			t.Errorf("%s: got bodies %q, want the body sent twice", method, bodies)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	gd := Godradis{}
+	if err := gd.ConfigureWithOptions(server.URL, "abc", WithRateLimit(20)); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := gd.Raw("GET", "teams", nil, nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	// The first request goes straight away and the other five are spaced 50ms apart
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("6 requests at 20 per second took %v", elapsed)
+	}
+	if err := (&Godradis{}).ConfigureWithOptions(server.URL, "abc", WithRateLimit(-1)); err == nil {
+		t.Error("expected an error for a negative rate limit")
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	gd := Godradis{}
+	err := gd.ConfigureWithOptions("https://example.com", "abc", WithMinTLSVersion(tls.VersionTLS12),
+		WithCipherSuites(tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig := gd.httpClient.Transport.(*http.Transport).TLSClientConfig
+	if tlsConfig.MinVersion != tls.VersionTLS12 ||
+		!reflect.DeepEqual(tlsConfig.CipherSuites, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}) {
+		t.Errorf("got MinVersion %#x and CipherSuites %v", tlsConfig.MinVersion, tlsConfig.CipherSuites)
+	}
+
+	gd = Godradis{}
+	gd.Configure("https://example.com", "abc", true)
+	tlsConfig = gd.httpClient.Transport.(*http.Transport).TLSClientConfig
+	if tlsConfig.MinVersion != 0 || tlsConfig.CipherSuites != nil {
+		t.Errorf("got non-default MinVersion %#x and CipherSuites %v", tlsConfig.MinVersion, tlsConfig.CipherSuites)
+	}
+
+	// A server that tops out below the configured minimum fails the handshake
+	server := httptest.NewUnstartedServer(newFakeDradis(map[string]string{"GET /teams": `[]`}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+	for _, tt := range []struct {
+		version uint16
+		wantErr bool
+	}{
+		{tls.VersionTLS12, false},
+		{tls.VersionTLS13, true},
+	} {
+		gd = Godradis{}
+		err = gd.ConfigureWithOptions(server.URL, "abc", WithVerify(false), WithMinTLSVersion(tt.version))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = gd.GetAllTeams()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("minimum %#x: got error %v, want error %v", tt.version, err, tt.wantErr)
+		}
+	}
+}
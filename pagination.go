@@ -0,0 +1,50 @@
+package godradis
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ListResult holds the pagination metadata of a single page of a list endpoint. TotalCount and PerPage are -1 if the
+// server didn't report them.
+type ListResult struct {
+	TotalCount int
+	Page int
+	PerPage int
+}
+
+type IssuesPage struct {
+	ListResult
+	Items []Issue
+}
+
+type NodesPage struct {
+	ListResult
+	Items []Node
+}
+
+// parseListResult reads the pagination headers sent by the server. A missing page number falls back to the requested page.
+// The page size isn't guessed from the number of items returned, since the last page is usually short.
+func parseListResult(header http.Header, page int) ListResult {
+	lr := ListResult{TotalCount: -1, Page: page, PerPage: -1}
+	if total, ok := headerInt(header, "X-Total-Count", "Total"); ok {
+		lr.TotalCount = total
+	}
+	if p, ok := headerInt(header, "X-Page", "Page"); ok {
+		lr.Page = p
+	}
+	if perPage, ok := headerInt(header, "X-Per-Page", "Per-Page"); ok {
+		lr.PerPage = perPage
+	}
+	return lr
+}
+
+// headerInt returns the value of the first of names that is present in header and holds an integer
+func headerInt(header http.Header, names ...string) (int, bool) {
+	for _, name := range names {
+		if value, err := strconv.Atoi(header.Get(name)); err == nil {
+			return value, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,164 @@
+package godradis
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParseListResult(t *testing.T) {
+	header := http.Header{}
+	if lr := parseListResult(header, 3); lr != (ListResult{TotalCount: -1, Page: 3, PerPage: -1}) {
+		t.Errorf("got %+v without headers", lr)
+	}
+	header.Set("X-Total-Count", "57")
+	header.Set("X-Page", "2")
+	header.Set("Per-Page", "25")
+	if lr := parseListResult(header, 3); lr != (ListResult{TotalCount: 57, Page: 2, PerPage: 25}) {
+		t.Errorf("got %+v", lr)
+	}
+}
+
+// pagedHandler serves pages[n-1] for ?page=n and an empty list past the last page. If ignorePage is set every request is
+// answered with the first page, like a server that does not paginate the endpoint. The number of requests is counted in
+// *requests.
+func pagedHandler(pages []string, ignorePage bool, requests *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if ignorePage {
+			page = 1
+		}
+		if page < 1 || page > len(pages) {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(pages[page-1]))
+	}
+}
+
+func TestGetAllTeamsPages(t *testing.T) {
+	pages := []string{`[{"id": 1, "name": "Red"}, {"id": 2, "name": "Blue"}]`, `[{"id": 3, "name": "Green"}]`}
+	for _, ignorePage := range []bool{false, true} {
+		var requests int32
+		gd, _ := newTestClient(t, pagedHandler(pages, ignorePage, &requests))
+		teams, err := gd.GetAllTeams()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, team := range teams {
+			names = append(names, team.Name)
+		}
+		want := []string{"Red", "Blue", "Green"}
+		wantRequests := int32(3)
+		if ignorePage {
+			want = want[:2]
+			wantRequests = 2
+		}
+		if !reflect.DeepEqual(names, want) {
+			t.Errorf("ignorePage %v: got teams %v, want %v", ignorePage, names, want)
+		}
+		if got := atomic.LoadInt32(&requests); got != wantRequests {
+			t.Errorf("ignorePage %v: got %v requests, want %v", ignorePage, got, wantRequests)
+		}
+	}
+}
+
+func TestNodesIteratorPages(t *testing.T) {
+	pages := []string{`[{"id": 1, "label": "10.0.0.1"}, {"id": 2, "label": "10.0.0.2"}]`, `[{"id": 3, "label": "10.0.0.3"}]`}
+	for _, ignorePage := range []bool{false, true} {
+		var requests int32
+		gd, _ := newTestClient(t, pagedHandler(pages, ignorePage, &requests))
+		project := Project{Id: 1}
+		var ids []int
+		err := gd.NodesIterator(&project, func(node *Node) error {
+			if node.Project != &project {
+				t.Errorf("node %v has no project reference", node.Id)
+			}
+			ids = append(ids, node.Id)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []int{1, 2, 3}
+		if ignorePage {
+			want = want[:2]
+		}
+		if !reflect.DeepEqual(ids, want) {
+			t.Errorf("ignorePage %v: got nodes %v, want %v", ignorePage, ids, want)
+		}
+	}
+}
+
+func TestCountNodes(t *testing.T) {
+	pages := []string{`[{"id": 1}, {"id": 2}]`, `[{"id": 2}, {"id": 3}]`}
+	for _, ignorePage := range []bool{false, true} {
+		var requests int32
+		gd, _ := newTestClient(t, pagedHandler(pages, ignorePage, &requests))
+		count, err := gd.CountNodes(&Project{Id: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := 3
+		if ignorePage {
+			want = 2
+		}
+		if count != want {
+			t.Errorf("ignorePage %v: got count %v, want %v", ignorePage, count, want)
+		}
+	}
+}
+
+func TestCountIssuesUsesTotalHeader(t *testing.T) {
+	var requests int32
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Count", "57")
+		w.Write([]byte(`[{"id": 1}]`))
+	}))
+	count, err := gd.CountIssues(&Project{Id: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&requests); count != 57 || got != 1 {
+		t.Errorf("got count %v after %v requests, want 57 after 1", count, got)
+	}
+}
+
+func TestCountHeaders(t *testing.T) {
+	tests := []struct {
+		header string
+		value string
+		want int
+		wantRequests int32
+	}{
+		{"X-Total-Count", "120", 120, 1},
+		{"Total", "0", 0, 1},
+		// An unparseable total falls back to counting the listed IDs page by page
+		{"X-Total-Count", "many", 3, 3},
+	}
+	pages := []string{`[{"id": 1}, {"id": 2}]`, `[{"id": 3}]`}
+	for _, tt := range tests {
+		for _, count := range []func(*Godradis, *Project) (int, error){(*Godradis).CountNodes, (*Godradis).CountIssues} {
+			var requests int32
+			handler := pagedHandler(pages, false, &requests)
+			gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(tt.header, tt.value)
+				handler(w, r)
+			}))
+			got, err := count(gd, &Project{Id: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := atomic.LoadInt32(&requests); got != tt.want || n != tt.wantRequests {
+				t.Errorf("%s: %s: got %v after %v requests, want %v after %v", tt.header, tt.value, got, n, tt.want, tt.wantRequests)
+			}
+		}
+	}
+}
@@ -1,10 +1,30 @@
 package godradis
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type Client struct {
 	Id int `json:"id"`
 	Name string `json:"name"`
 }
 
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings.
+func (c *Client) UnmarshalJSON(data []byte) error {
+	type client Client
+	aux := struct {
+		*client
+		Id flexInt `json:"id"`
+	}{client: (*client)(c), Id: flexInt(c.Id)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	c.Id = int(aux.Id)
+	return nil
+}
+
 type Author struct {
 	Email string `json:"email"`
 }
@@ -13,6 +33,26 @@ type Owner struct {
 	Email string `json:"email"`
 }
 
+type ProjectTemplate struct {
+	Id int `json:"id"`
+	Name string `json:"name"`
+}
+
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings.
+func (p *ProjectTemplate) UnmarshalJSON(data []byte) error {
+	type projectTemplate ProjectTemplate
+	aux := struct {
+		*projectTemplate
+		Id flexInt `json:"id"`
+	}{projectTemplate: (*projectTemplate)(p), Id: flexInt(p.Id)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	p.Id = int(aux.Id)
+	return nil
+}
+
 type Project struct {
 	Id int `json:"id"`
 	Name string `json:"name"`
@@ -21,4 +61,36 @@ type Project struct {
 	UpdatedAt string `json:"updated_at"`
 	Authors []Author `json:"authors"`
 	Owners []Owner `json:"owners"`
-}
\ No newline at end of file
+	Deleted bool `json:"-"` // Set by DeleteProject
+}
+
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings.
+func (p *Project) UnmarshalJSON(data []byte) error {
+	type project Project
+	aux := struct {
+		*project
+		Id flexInt `json:"id"`
+	}{project: (*project)(p), Id: flexInt(p.Id)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	p.Id = int(aux.Id)
+	return nil
+}
+
+// ProjectIssueCount pairs a Project with the number of issues in it, as returned by GetAllProjectsWithIssueCounts.
+type ProjectIssueCount struct {
+	Project Project
+	IssueCount int
+}
+
+// CreatedTime parses CreatedAt into a time.Time.
+func (p *Project) CreatedTime() (time.Time, error) {
+	return parseTimestamp(p.CreatedAt)
+}
+
+// UpdatedTime parses UpdatedAt into a time.Time.
+func (p *Project) UpdatedTime() (time.Time, error) {
+	return parseTimestamp(p.UpdatedAt)
+}
@@ -0,0 +1,292 @@
+package godradis
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testProjectTemplates = `[
+	{"id": 1, "name": "Welcome"},
+	{"id": 2, "name": "Web Application"},
+	{"id": 3, "name": "Web Application"}
+]`
+
+func TestCreateProjectTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		template interface{}
+		strict bool
+		wantTemplate string
+		wantErr string
+	}{
+		{"unambiguous name", "Welcome", false, "Welcome", ""},
+		{"unambiguous ProjectTemplate", &ProjectTemplate{Id: 1, Name: "Welcome"}, false, "Welcome", ""},
+		{"ambiguous name", "Web Application", false, "", "ambiguous"},
+		{"ambiguous ProjectTemplate", ProjectTemplate{Id: 3, Name: "Web Application"}, false, "", "ambiguous"},
+		{"mismatched ProjectTemplate", ProjectTemplate{Id: 2, Name: "Welcome"}, false, "", "not named"},
+		{"unknown name", "Internal", false, "Internal", ""},
+		{"unknown name, strict", "Internal", true, "", "unknown project template Internal (available: Welcome, Web Application, Web Application)"},
+		{"invalid type", 4, false, "", "must be a string or a ProjectTemplate"},
+	}
+	for _, tt := range tests {
+		fake := newFakeDradis(map[string]string{
+			"GET /project_templates": testProjectTemplates,
+			"POST /projects": `{"id": 9, "name": "New Project"}`,
+		})
+		gd, _ := newTestClient(t, fake)
+		gd.Config.StrictTemplates = tt.strict
+		project, err := gd.CreateProject("New Project", 1, nil, nil, tt.template)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: got error %v, want %q", tt.name, err, tt.wantErr)
+			}
+			if sent := fake.sent(); len(sent) != 0 {
+				t.Errorf("%s: unexpected requests %v", tt.name, sent)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if project.Id != 9 {
+			t.Errorf("%s: got project %v", tt.name, project.Id)
+		}
+		if body := fake.bodies["POST /projects"]; !strings.Contains(body, `"template":"`+tt.wantTemplate+`"`) {
+			t.Errorf("%s: sent %s", tt.name, body)
+		}
+	}
+}
+
+func TestGetAllProjectTemplates(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{"GET /project_templates": testProjectTemplates}))
+	templates, err := gd.GetAllProjectTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ProjectTemplate{{Id: 1, Name: "Welcome"}, {Id: 2, Name: "Web Application"}, {Id: 3, Name: "Web Application"}}
+	if !reflect.DeepEqual(templates, want) {
+		t.Errorf("got %+v, want %+v", templates, want)
+	}
+}
+
+func TestCreateProjectTemplateListingFails(t *testing.T) {
+	fake := newFakeDradis(map[string]string{"POST /projects": `{"id": 9, "name": "New Project"}`})
+	gd, _ := newTestClient(t, fake)
+	if _, err := gd.CreateProject("New Project", 1, nil, nil, "Welcome"); err != nil {
+		t.Errorf("got %v, want the name sent unchecked", err)
+	}
+	gd.Config.StrictTemplates = true
+	if _, err := gd.CreateProject("New Project", 1, nil, nil, "Welcome"); err == nil {
+		t.Error("expected an error with StrictTemplates set")
+	}
+	if sent := fake.sent(); !reflect.DeepEqual(sent, []string{"POST /projects"}) {
+		t.Errorf("got requests %v", sent)
+	}
+}
+
+func TestDeletedProjectNodes(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"DELETE /projects/1": `{"message": "Resource deleted successfully"}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1}
+	node := Node{Id: 2, Project: &project}
+	if err := gd.DeleteProject(&project); err != nil {
+		t.Fatal(err)
+	}
+	if !project.Deleted {
+		t.Error("expected the project to be marked deleted")
+	}
+	if err := gd.UpdateNode(&node, "localhost", nil, nil, nil); !errors.Is(err, ErrProjectDeleted) {
+		t.Errorf("got error %v updating a node of a deleted project", err)
+	}
+	// Another copy of the project is stale too, since deletion is tracked by ID
+	if _, err := gd.GetAllNodes(&Project{Id: 1}); !errors.Is(err, ErrProjectDeleted) {
+		t.Errorf("got error %v listing the nodes of a deleted project", err)
+	}
+	if sent := fake.sent(); !reflect.DeepEqual(sent, []string{"DELETE /projects/1"}) {
+		t.Errorf("got requests %v", sent)
+	}
+}
+
+func TestUpdateProjectPreservingAuthors(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /users": `[{"id": 3, "email": "alice@example.com"}, {"id": 5, "email": "bob@example.com"}, {"id": 6, "email": "eve@example.com"}]`,
+		"PUT /projects/1": `{"id": 1, "name": "Renamed", "authors": [{"email": "alice@example.com"}, {"email": "bob@example.com"}]}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	project := Project{Id: 1, Name: "Original", Authors: []Author{{Email: "Bob@example.com"}, {Email: "alice@example.com"}}}
+	if err := gd.UpdateProjectPreservingAuthors(&project, "Renamed", nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"project":{"name":"Renamed","author_ids":[5,3]}}`
+	if body := fake.bodies["PUT /projects/1"]; body != want {
+		t.Errorf("got body %s, want %s", body, want)
+	}
+
+	project.Authors = append(project.Authors, Author{Email: "mallory@example.com"})
+	err := gd.UpdateProjectPreservingAuthors(&project, "Renamed again", nil, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "mallory@example.com") {
+		t.Errorf("got error %v for an author without a user", err)
+	}
+	if sent := fake.sent(); len(sent) != 1 {
+		t.Errorf("got requests %v, want only the first update", sent)
+	}
+}
+
+func TestGetProjectsUpdatedSince(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /projects": `[
+			{"id": 1, "name": "Old", "updated_at": "2020-01-01T00:00:00.000Z"},
+			{"id": 2, "name": "Boundary", "updated_at": "2021-06-01T12:00:00.000Z"},
+			{"id": 3, "name": "Recent", "updated_at": "2021-06-01T13:30:00.000+01:00"},
+			{"id": 4, "name": "New", "updated_at": "2022-03-04T05:06:07.000Z"}
+		]`,
+	}))
+	since := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	projects, err := gd.GetProjectsUpdatedSince(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, project := range projects {
+		names = append(names, project.Name)
+	}
+	if want := []string{"Recent", "New"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
+
+func TestGetProjectsUpdatedSinceRejectsBadTimestamps(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /projects": `[{"id": 1, "name": "Broken", "updated_at": "yesterday"}]`,
+	}))
+	if _, err := gd.GetProjectsUpdatedSince(time.Time{}); err == nil {
+		t.Error("expected an error for an unparseable timestamp")
+	}
+}
+
+func TestGetAllProjectsPartial(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+		wantErr string
+	}{
+		{"valid", `[{"id": 1, "name": "A"}, {"id": 2, "name": "B"}]`, []string{"A", "B"}, ""},
+		{"mismatched type", `[{"id": 1, "name": "A"}, {"id": 2, "name": 5}, {"id": "x", "name": "C"}, {"id": 4, "name": "D"}]`,
+			[]string{"A", "D"}, "could not decode all projects: project 1: "},
+		{"malformed", `[{"id": 1, "name": "A"}, {"id": 2, "name": "B"}, {"id": 3, "name": }, {"id": 4, "name": "D"}]`,
+			[]string{"A", "B"}, "project 2: "},
+		{"not a list", `{"id": 1, "name": "A"}`, nil, "response is not a list"},
+	}
+	for _, tt := range tests {
+		gd, _ := newTestClient(t, newFakeDradis(map[string]string{"GET /projects": tt.body}))
+		projects, err := gd.GetAllProjectsPartial()
+		var names []string
+		for _, project := range projects {
+			names = append(names, project.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("%s: got projects %v, want %v", tt.name, names, tt.want)
+		}
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{"GET /projects": tests[1].body}))
+	if projects, err := gd.GetAllProjects(); err == nil || len(projects) != 0 {
+		t.Errorf("got %v projects and error %v from GetAllProjects, want none and an error", len(projects), err)
+	}
+}
+
+func TestGetProjectsByIds(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/pro/api/projects/"))
+		if id == 99 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// Later projects answer first, so the results only come back in order if they are reordered
+		time.Sleep(time.Duration(10-id) * 5 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": %v, "name": "Project %v"}`, id, id)
+	}))
+	projects, err := gd.GetProjectsByIds([]int{5, 1, 99, 3, 2, 4})
+	if err == nil || !strings.Contains(err.Error(), "could not get 1 of 6 projects: project 99") {
+		t.Errorf("got error %v", err)
+	}
+	var ids []int
+	for _, project := range projects {
+		ids = append(ids, project.Id)
+	}
+	if want := []int{5, 1, 3, 2, 4}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got projects %v, want %v", ids, want)
+	}
+}
+
+func TestGetAllProjectsWithIssueCounts(t *testing.T) {
+	var inFlight, maxInFlight int32
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/pro/api/projects" {
+			w.Write([]byte(`[{"id": 1, "name": "A"}, {"id": 2, "name": "B"}, {"id": 3, "name": "C"}, {"id": 4, "name": "D"},
+				{"id": 5, "name": "E"}, {"id": 6, "name": "F"}]`))
+			return
+		}
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		page := r.URL.Query().Get("page")
+		switch r.Header.Get("Dradis-Project-Id") {
+		case "1":
+			w.Header().Set("X-Total-Count", "40")
+			w.Write([]byte(`[{"id": 1}]`))
+		case "2":
+			// No total header, so the pages are counted
+			pages := map[string]string{"1": `[{"id": 1}, {"id": 2}]`, "2": `[{"id": 3}]`}
+			body, ok := pages[page]
+			if !ok {
+				body = `[]`
+			}
+			w.Write([]byte(body))
+		case "4":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "5":
+			w.Header().Set("Total", "7")
+			w.Write([]byte(`[{"id": 1}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	counts, err := gd.GetAllProjectsWithIssueCounts()
+	if err == nil || !strings.Contains(err.Error(), "could not count issues in 1 of 6 projects: project 4") {
+		t.Errorf("got error %v", err)
+	}
+	var got []string
+	for _, pc := range counts {
+		got = append(got, fmt.Sprintf("%s: %v", pc.Project.Name, pc.IssueCount))
+	}
+	want := []string{"A: 40", "B: 3", "C: 0", "E: 7", "F: 0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > maxConcurrentRequests {
+		t.Errorf("got %v concurrent requests, want at most %v", max, maxConcurrentRequests)
+	}
+}
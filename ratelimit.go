@@ -0,0 +1,40 @@
+package godradis
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter spaces requests evenly so that no more than the configured number are sent per second, however many
+// goroutines are sending them
+type rateLimiter struct {
+	mu sync.Mutex
+	next time.Time // Earliest time the next request may be sent
+}
+
+// wait blocks until a request may be sent at perSecond requests per second, or until ctx is done. A perSecond of zero or
+// less means no limit.
+func (l *rateLimiter) wait(ctx context.Context, perSecond float64) error {
+	if perSecond <= 0 {
+		return nil
+	}
+	interval := time.Duration(float64(time.Second) / perSecond)
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(interval)
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
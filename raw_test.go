@@ -0,0 +1,137 @@
+package godradis
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRawGet(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/pro/api/nodes/12/evidence" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if r.Header.Get("Dradis-Project-Id") != "45" {
+			t.Errorf("got project id header %q", r.Header.Get("Dradis-Project-Id"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": 1}]`))
+	}))
+	projectId := 45
+	body, status, err := gd.Raw("GET", "nodes/12/evidence", &projectId, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusOK || string(body) != `[{"id": 1}]` {
+		t.Errorf("got %v %s", status, body)
+	}
+}
+
+func TestRawPost(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "POST" || string(body) != `{"team":{"name":"Red"}}` {
+			t.Errorf("unexpected request %s %s", r.Method, body)
+		}
+		if r.Header.Get("Dradis-Project-Id") != "" {
+			t.Errorf("unexpected project id header")
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"errors": ["name taken"]}`))
+	}))
+	body, status, err := gd.Raw("POST", "teams", nil, []byte(`{"team":{"name":"Red"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusUnprocessableEntity || string(body) != `{"errors": ["name taken"]}` {
+		t.Errorf("got %v %s", status, body)
+	}
+}
+
+func TestRawNonJSON(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write([]byte("a,b\n1,2\n"))
+	}))
+	body, status, err := gd.Raw("GET", "export.csv", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusOK || string(body) != "a,b\n1,2\n" {
+		t.Errorf("got %v %q", status, body)
+	}
+}
+
+func TestRawInProject(t *testing.T) {
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "PUT" || r.URL.Path != "/pro/api/nodes/12" || string(body) != `{"node":{"label":"web01"}}` {
+			t.Errorf("unexpected request %s %s %s", r.Method, r.URL, body)
+		}
+		if r.Header.Get("Dradis-Project-Id") != "45" {
+			t.Errorf("got project id header %q, want 45", r.Header.Get("Dradis-Project-Id"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 12, "label": "web01"}`))
+	}))
+	project := Project{Id: 45, Name: "External"}
+	body, status, err := gd.RawInProject(&project, "PUT", "nodes/12", []byte(`{"node":{"label":"web01"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusOK || string(body) != `{"id": 12, "label": "web01"}` {
+		t.Errorf("got %v %s", status, body)
+	}
+	if _, _, err = gd.RawInProject(nil, "GET", "nodes", nil); err == nil {
+		t.Error("expected an error without a project")
+	}
+}
+
+func TestGetReaderStreams(t *testing.T) {
+	const chunkSize = 1 << 20
+	const chunks = 8
+	firstChunkRead := make(chan struct{})
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pro/api/exports/12/download" || r.Header.Get("Dradis-Project-Id") != "45" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		chunk := bytes.Repeat([]byte("x"), chunkSize)
+		w.Write(chunk)
+		w.(http.Flusher).Flush()
+		// The rest of the body is only sent once the client has read the first chunk, which it can't do if the whole
+		// body is buffered before GetReader returns
+		select {
+		case <-firstChunkRead:
+		case <-time.After(5 * time.Second):
+			t.Error("the first chunk was not read before the body was complete")
+			return
+		}
+		for i := 1; i < chunks; i++ {
+			w.Write(chunk)
+		}
+	}))
+	projectId := 45
+	body, status, err := gd.GetReader("exports/12/download", &projectId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if status != http.StatusOK {
+		t.Errorf("got status %v", status)
+	}
+	if _, err = io.ReadFull(body, make([]byte, chunkSize)); err != nil {
+		t.Fatal(err)
+	}
+	close(firstChunkRead)
+	n, err := io.Copy(ioutil.Discard, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != chunkSize*(chunks-1) {
+		t.Errorf("got %v more bytes, want %v", n, chunkSize*(chunks-1))
+	}
+}
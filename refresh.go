@@ -0,0 +1,114 @@
+package godradis
+
+/*
+RefreshProject re-fetches the project from the Dradis server and updates p in place, so that pointers to p held by nodes
+and issues see the current values.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    [...]
+    err := gd.RefreshProject(&project)
+ */
+func (gd *Godradis) RefreshProject(p *Project) error {
+	project, err := gd.GetProjectById(p.Id)
+	if err != nil {
+		return err
+	}
+	*p = project
+	return nil
+}
+
+/*
+RefreshNode re-fetches the node from the Dradis server and updates n in place. n keeps its Project reference, and the
+refreshed Evidence and Notes point back to n.
+
+    node, _ := gd.GetNodeByLabel(&project, "127.0.0.1")
+    [...]
+    err := gd.RefreshNode(&node)
+ */
+func (gd *Godradis) RefreshNode(n *Node) error {
+	_, err := n.projectId()
+	if err != nil {
+		return err
+	}
+	node, err := gd.GetNodeById(n.Project, n.Id)
+	if err != nil {
+		return err
+	}
+	n.Mu.Lock()
+	defer n.Mu.Unlock()
+	n.Label = node.Label
+	n.TypeId = node.TypeId
+	n.ParentId = node.ParentId
+	n.Position = node.Position
+	n.CreatedAt = node.CreatedAt
+	n.UpdatedAt = node.UpdatedAt
+	n.Evidence = node.Evidence
+	n.Notes = node.Notes
+	n.setEvidenceNodeReferences()
+	n.setNoteNodeReferences()
+	return nil
+}
+
+/*
+RefreshIssue re-fetches the issue from the Dradis server and updates i in place. i keeps its Project reference.
+
+    issue, _ := gd.GetIssueByTitle(&project, "Cross-Site Scripting")
+    [...]
+    err := gd.RefreshIssue(&issue)
+ */
+func (gd *Godradis) RefreshIssue(i *Issue) error {
+	_, err := i.projectId()
+	if err != nil {
+		return err
+	}
+	issue, err := gd.GetIssueById(i.Project, i.Id)
+	if err != nil {
+		return err
+	}
+	*i = issue
+	return nil
+}
+
+/*
+RefreshEvidence re-fetches the evidence from the Dradis server and updates e in place. e keeps its Node reference.
+
+    evidence, _ := gd.GetEvidenceById(&node, 2)
+    [...]
+    err := gd.RefreshEvidence(&evidence)
+ */
+func (gd *Godradis) RefreshEvidence(e *Evidence) error {
+	_, err := e.projectId()
+	if err != nil {
+		return err
+	}
+	evidence, err := gd.GetEvidenceById(e.Node, e.Id)
+	if err != nil {
+		return err
+	}
+	*e = evidence
+	return nil
+}
+
+/*
+RefreshNote re-fetches the note from the Dradis server and updates n in place. n keeps its Node reference.
+
+    note, _ := gd.GetNoteById(&node, 3)
+    [...]
+    err := gd.RefreshNote(&note)
+ */
+func (gd *Godradis) RefreshNote(n *Note) error {
+	_, err := n.projectId()
+	if err != nil {
+		return err
+	}
+	note, err := gd.GetNoteById(n.Node, n.Id)
+	if err != nil {
+		return err
+	}
+	*n = note
+	return nil
+}
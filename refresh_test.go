@@ -0,0 +1,91 @@
+package godradis
+
+import (
+	"testing"
+)
+
+func TestRefreshProjectNodeAndIssue(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /projects/1": `{"id": 1, "name": "External"}`,
+		"GET /nodes/2": `{"id": 2, "label": "10.0.0.1", "evidence": [{"id": 20, "content": "old"}], "notes": []}`,
+		"GET /issues/3": `{"id": 3, "title": "XSS", "fields": {"Title": "XSS"}}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	project, err := gd.GetProjectById(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := gd.GetNodeById(&project, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue, err := gd.GetIssueById(&project, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.setRoute("GET /projects/1", `{"id": 1, "name": "External (retest)"}`)
+	fake.setRoute("GET /nodes/2", `{"id": 2, "label": "web01", "position": 4,
+		"evidence": [{"id": 20, "content": "new"}], "notes": [{"id": 21, "text": "#[Title]#\r\nNote"}]}`)
+	fake.setRoute("GET /issues/3", `{"id": 3, "title": "Stored XSS", "fields": {"Title": "Stored XSS"}}`)
+
+	if err = gd.RefreshProject(&project); err != nil {
+		t.Fatal(err)
+	}
+	if project.Name != "External (retest)" {
+		t.Errorf("got project name %v", project.Name)
+	}
+	if node.Project.Name != "External (retest)" {
+		t.Errorf("got node project name %v, want the refreshed name", node.Project.Name)
+	}
+
+	if err = gd.RefreshNode(&node); err != nil {
+		t.Fatal(err)
+	}
+	if node.Label != "web01" || node.Position != 4 || node.Project != &project {
+		t.Errorf("got node %v at %v in project %p", node.Label, node.Position, node.Project)
+	}
+	if len(node.Evidence) != 1 || node.Evidence[0].Content != "new" || node.Evidence[0].Node != &node {
+		t.Errorf("got evidence %+v", node.Evidence)
+	}
+	if len(node.Notes) != 1 || node.Notes[0].Node != &node {
+		t.Errorf("got notes %+v", node.Notes)
+	}
+
+	if err = gd.RefreshIssue(&issue); err != nil {
+		t.Fatal(err)
+	}
+	if issue.Title != "Stored XSS" || issue.Project != &project {
+		t.Errorf("got issue %v in project %p", issue.Title, issue.Project)
+	}
+}
+
+func TestRefreshEvidenceAndNote(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /nodes/2/evidence/20": `{"id": 20, "content": "#[Output]#\r\nold", "fields": {"Output": "old"}}`,
+		"GET /nodes/2/notes/21": `{"id": 21, "text": "#[Title]#\r\nold", "fields": {"Title": "old"}}`,
+	})
+	gd, _ := newTestClient(t, fake)
+	node := Node{Id: 2, Project: &Project{Id: 1}}
+	evidence := Evidence{Id: 20, Node: &node}
+	note := Note{Id: 21, Node: &node}
+
+	fake.setRoute("GET /nodes/2/evidence/20", `{"id": 20, "content": "#[Output]#\r\nnew", "fields": {"Output": "new"}}`)
+	fake.setRoute("GET /nodes/2/notes/21", `{"id": 21, "text": "#[Title]#\r\nnew", "fields": {"Title": "new"}}`)
+	if err := gd.RefreshEvidence(&evidence); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := evidence.Fields.Get("Output"); v != "new" || evidence.Node != &node {
+		t.Errorf("got evidence output %v on node %p", v, evidence.Node)
+	}
+	if err := gd.RefreshNote(&note); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := note.Fields.Get("Title"); v != "new" || note.Node != &node {
+		t.Errorf("got note title %v on node %p", v, note.Node)
+	}
+
+	if err := gd.RefreshNote(&Note{Id: 21}); err == nil {
+		t.Error("expected an error refreshing a note without a Node reference")
+	}
+}
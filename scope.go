@@ -0,0 +1,86 @@
+package godradis
+
+import "github.com/iancoleman/orderedmap"
+
+// ProjectScope wraps a Godradis client and a Project so that project-level calls don't need the *Project argument.
+type ProjectScope struct {
+	gd *Godradis
+	project *Project
+}
+
+/*
+Project returns a ProjectScope bound to p. The scope's methods call the matching Godradis methods with p as the project,
+so the Dradis-Project-Id header is always set from p.
+
+    gd := godradis.Godradis{}
+
+    [...]
+
+    project, _ := gd.GetProjectByName("Foobar External Network Penetration Test")
+    ps := gd.Project(&project)
+    nodes, _ := ps.Nodes()
+    issue, _ := ps.IssueByTitle("Cross-Site Scripting")
+ */
+func (gd *Godradis) Project(p *Project) *ProjectScope {
+	return &ProjectScope{gd, p}
+}
+
+// GetProject returns the Project the scope is bound to.
+func (ps *ProjectScope) GetProject() *Project {
+	return ps.project
+}
+
+// Nodes lists the project's nodes. See Godradis.GetAllNodes.
+func (ps *ProjectScope) Nodes(opts ...NodeOption) ([]Node, error) {
+	return ps.gd.GetAllNodes(ps.project, opts...)
+}
+
+// NodeById fetches a node in the project by ID. See Godradis.GetNodeById.
+func (ps *ProjectScope) NodeById(id int) (Node, error) {
+	return ps.gd.GetNodeById(ps.project, id)
+}
+
+// NodeByLabel fetches a node in the project by label. See Godradis.GetNodeByLabel.
+func (ps *ProjectScope) NodeByLabel(label string) (Node, error) {
+	return ps.gd.GetNodeByLabel(ps.project, label)
+}
+
+// CreateNode creates a node in the project. See Godradis.CreateNode.
+func (ps *ProjectScope) CreateNode(label string, typeId NodeType, parentId int, position int) (Node, error) {
+	return ps.gd.CreateNode(ps.project, label, typeId, parentId, position)
+}
+
+// GetOrCreateNode returns the project's top-level node with label, creating it if needed. See Godradis.GetOrCreateNode.
+func (ps *ProjectScope) GetOrCreateNode(label string, typeId NodeType) (*Node, bool, error) {
+	return ps.gd.GetOrCreateNode(ps.project, label, typeId)
+}
+
+// Issues lists the project's issues. See Godradis.GetAllIssues.
+func (ps *ProjectScope) Issues() ([]Issue, error) {
+	return ps.gd.GetAllIssues(ps.project)
+}
+
+// IssueById fetches an issue in the project by ID. See Godradis.GetIssueById.
+func (ps *ProjectScope) IssueById(id int) (Issue, error) {
+	return ps.gd.GetIssueById(ps.project, id)
+}
+
+// IssueByTitle fetches an issue in the project by title. See Godradis.GetIssueByTitle.
+func (ps *ProjectScope) IssueByTitle(title string) (Issue, error) {
+	return ps.gd.GetIssueByTitle(ps.project, title)
+}
+
+// CreateIssue creates an issue in the project from fields. See Godradis.CreateIssue.
+func (ps *ProjectScope) CreateIssue(fields *orderedmap.OrderedMap) (Issue, error) {
+	return ps.gd.CreateIssue(ps.project, fields)
+}
+
+// CreateIssueFromText creates an issue in the project from raw text. See Godradis.CreateIssueFromText.
+func (ps *ProjectScope) CreateIssueFromText(text string) (Issue, error) {
+	return ps.gd.CreateIssueFromText(ps.project, text)
+}
+
+// GetOrCreateIssue returns the project's issue with title, creating it from fields if needed. See Godradis.GetOrCreateIssue.
+func (ps *ProjectScope) GetOrCreateIssue(title string, fields *orderedmap.OrderedMap) (Issue, bool, error) {
+	return ps.gd.GetOrCreateIssue(ps.project, title, fields)
+}
@@ -0,0 +1,54 @@
+package godradis
+
+import (
+	"github.com/iancoleman/orderedmap"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestProjectScopeSendsProjectId(t *testing.T) {
+	fake := newFakeDradis(map[string]string{
+		"GET /nodes": `[{"id": 2, "label": "10.0.0.1"}]`,
+		"GET /nodes/2": `{"id": 2, "label": "10.0.0.1"}`,
+		"POST /nodes": `{"id": 3, "label": "10.0.0.2"}`,
+		"GET /issues": `[{"id": 4, "title": "XSS"}]`,
+		"GET /issues/4": `{"id": 4, "title": "XSS"}`,
+		"POST /issues": `{"id": 5, "title": "SQLi"}`,
+	})
+	var mu sync.Mutex
+	projectIds := make(map[string]bool)
+	gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		projectIds[r.Header.Get("Dradis-Project-Id")] = true
+		mu.Unlock()
+		fake.ServeHTTP(w, r)
+	}))
+	ps := gd.Project(&Project{Id: 7})
+	fields := orderedmap.New()
+	fields.Set("Title", "SQLi")
+	calls := map[string]func() error{
+		"Nodes": func() error { _, err := ps.Nodes(); return err },
+		"NodeById": func() error { _, err := ps.NodeById(2); return err },
+		"NodeByLabel": func() error { _, err := ps.NodeByLabel("10.0.0.1"); return err },
+		"CreateNode": func() error { _, err := ps.CreateNode("10.0.0.2", NodeTypeHost, 0, 0); return err },
+		"GetOrCreateNode": func() error { _, _, err := ps.GetOrCreateNode("10.0.0.2", NodeTypeHost); return err },
+		"Issues": func() error { _, err := ps.Issues(); return err },
+		"IssueById": func() error { _, err := ps.IssueById(4); return err },
+		"IssueByTitle": func() error { _, err := ps.IssueByTitle("XSS"); return err },
+		"CreateIssue": func() error { _, err := ps.CreateIssue(fields); return err },
+		"CreateIssueFromText": func() error { _, err := ps.CreateIssueFromText("#[Title]#\r\nSQLi"); return err },
+		"GetOrCreateIssue": func() error { _, _, err := ps.GetOrCreateIssue("SQLi", fields); return err },
+	}
+	for name, call := range calls {
+		if err := call(); err != nil {
+			t.Errorf("%s: got error %v", name, err)
+		}
+	}
+	if len(projectIds) != 1 || !projectIds["7"] {
+		t.Errorf("got Dradis-Project-Id values %v, want only 7", projectIds)
+	}
+	if ps.GetProject().Id != 7 {
+		t.Errorf("got project %v", ps.GetProject().Id)
+	}
+}
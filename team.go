@@ -1,5 +1,7 @@
 package godradis
 
+import "encoding/json"
+
 type Team struct {
 	Id int `json:"id"`
 	Name string `json:"name"`
@@ -9,7 +11,37 @@ type Team struct {
 	Projects []TeamProject `json:"projects"`
 }
 
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings.
+func (t *Team) UnmarshalJSON(data []byte) error {
+	type team Team
+	aux := struct {
+		*team
+		Id flexInt `json:"id"`
+	}{team: (*team)(t), Id: flexInt(t.Id)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	t.Id = int(aux.Id)
+	return nil
+}
+
 type TeamProject struct {
 	Id int `json:"id"`
 	Name string `json:"name"`
-}
\ No newline at end of file
+}
+
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings.
+func (t *TeamProject) UnmarshalJSON(data []byte) error {
+	type teamProject TeamProject
+	aux := struct {
+		*teamProject
+		Id flexInt `json:"id"`
+	}{teamProject: (*teamProject)(t), Id: flexInt(t.Id)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	t.Id = int(aux.Id)
+	return nil
+}
@@ -0,0 +1,51 @@
+package godradis
+
+import (
+	"strings"
+	"testing"
+)
+
+const testTeams = `[{"id": 1, "name": "Acme"}, {"id": 2, "name": "ACME"}, {"id": 3, "name": "Initech"}]`
+
+func TestTeamNamesCaseSensitivity(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{"GET /teams": testTeams}))
+
+	team, err := gd.GetTeamByName("acme")
+	if err != nil || team.Id != 1 {
+		t.Errorf("case-insensitive lookup got team %v, error %v", team.Id, err)
+	}
+	teams, err := gd.GetTeamsByName("cm")
+	if err != nil || len(teams) != 2 {
+		t.Errorf("case-insensitive search got %v teams, error %v", len(teams), err)
+	}
+
+	gd.Config.CaseSensitiveNames = true
+	team, err = gd.GetTeamByName("ACME")
+	if err != nil || team.Id != 2 {
+		t.Errorf("case-sensitive lookup got team %v, error %v", team.Id, err)
+	}
+	if _, err = gd.GetTeamByName("acme"); err == nil {
+		t.Error("case-sensitive lookup matched a name differing by case")
+	}
+	teams, err = gd.GetTeamsByName("CM")
+	if err != nil || len(teams) != 1 || teams[0].Id != 2 {
+		t.Errorf("case-sensitive search got %+v, error %v", teams, err)
+	}
+}
+
+func TestGetClientByName(t *testing.T) {
+	gd, _ := newTestClient(t, newFakeDradis(map[string]string{
+		"GET /teams": `[{"id": 1, "name": "Foobar Inc"}, {"id": 2, "name": "Acme"}]`,
+	}))
+	client, err := gd.GetClientByName("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client != (Client{Id: 2, Name: "Acme"}) {
+		t.Errorf("got %+v", client)
+	}
+	_, err = gd.GetClientByName("Initech")
+	if err == nil || !strings.Contains(err.Error(), "could not find client with name Initech") {
+		t.Errorf("got error %v for an unknown client", err)
+	}
+}
@@ -0,0 +1,26 @@
+package godradis
+
+import "encoding/json"
+
+type User struct {
+	Id int `json:"id"`
+	Email string `json:"email"`
+	Name string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// UnmarshalJSON accepts the integer fields as either JSON numbers or numeric strings.
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User
+	aux := struct {
+		*user
+		Id flexInt `json:"id"`
+	}{user: (*user)(u), Id: flexInt(u.Id)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	u.Id = int(aux.Id)
+	return nil
+}
@@ -0,0 +1,61 @@
+package godradis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ValidationError is returned by the create and update methods when the server rejects the request with a 422 status.
+// Fields maps each invalid field to the messages the server gave for it, e.g. "name" -> ["can't be blank"].
+type ValidationError struct {
+	Fields map[string][]string
+}
+
+func (ve *ValidationError) Error() string {
+	if len(ve.Fields) == 0 {
+		return "validation failed"
+	}
+	keys := make([]string, 0, len(ve.Fields))
+	for k := range ve.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var messages []string
+	for _, k := range keys {
+		for _, message := range ve.Fields[k] {
+			messages = append(messages, fmt.Sprintf("%s %s", k, message))
+		}
+	}
+	return fmt.Sprintf("validation failed: %s", strings.Join(messages, "; "))
+}
+
+// parseValidationError reads the body of a 422 response. Dradis sends the field errors either at the top level of the
+// object or wrapped in an "errors" object; values that aren't lists of strings are ignored.
+func parseValidationError(resp *http.Response) *ValidationError {
+	ve := &ValidationError{Fields: make(map[string][]string)}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ve
+	}
+	var raw map[string]json.RawMessage
+	if json.Unmarshal(body, &raw) != nil {
+		return ve
+	}
+	if wrapped, ok := raw["errors"]; ok {
+		var inner map[string]json.RawMessage
+		if json.Unmarshal(wrapped, &inner) == nil {
+			raw = inner
+		}
+	}
+	for k, v := range raw {
+		var messages []string
+		if json.Unmarshal(v, &messages) == nil {
+			ve.Fields[k] = messages
+		}
+	}
+	return ve
+}
@@ -0,0 +1,40 @@
+package godradis
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[string][]string
+		wantMsg string
+	}{
+		{"top level", `{"label": ["can't be blank", "is too short"], "position": ["is not a number"]}`,
+			map[string][]string{"label": {"can't be blank", "is too short"}, "position": {"is not a number"}},
+			"validation failed: label can't be blank; label is too short; position is not a number"},
+		{"wrapped", `{"errors": {"label": ["has already been taken"]}}`,
+			map[string][]string{"label": {"has already been taken"}}, "validation failed: label has already been taken"},
+		{"not a field map", `{"message": "Unprocessable"}`, map[string][]string{}, "validation failed"},
+		{"not json", `<html></html>`, map[string][]string{}, "validation failed"},
+	}
+	for _, tt := range tests {
+		gd, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(tt.body))
+		}))
+		_, err := gd.CreateNode(&Project{Id: 1}, "", NodeTypeDefault, 0, 0)
+		ve, ok := err.(*ValidationError)
+		if !ok {
+			t.Errorf("%s: got error %v, want a *ValidationError", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(ve.Fields, tt.want) || ve.Error() != tt.wantMsg {
+			t.Errorf("%s: got %v (%q), want %v (%q)", tt.name, ve.Fields, ve.Error(), tt.want, tt.wantMsg)
+		}
+	}
+}